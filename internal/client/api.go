@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/jsonapi"
+)
+
+// getAPI requests the JSON:API document at the path, relative to the API
+// base path, and decodes it into v.
+func (c *TFEClientImpl) getAPI(ctx context.Context, path string, v interface{}) error {
+	content, err := c.doAPI(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// doAPI sends a request to the path, relative to the API base path, for the
+// API features go-tfe does not support. The body, if any, is encoded as a
+// JSON:API document and the response body is returned.
+func (c *TFEClientImpl) doAPI(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	var reqBody []byte
+	if body != nil {
+		buf := bytes.Buffer{}
+		if err := jsonapi.MarshalPayloadWithoutIncluded(&buf, body); err != nil {
+			return nil, fmt.Errorf("error encoding the request: %w", err)
+		}
+		reqBody = buf.Bytes()
+	}
+
+	u := fmt.Sprintf("%s/api/v2/%s", strings.TrimRight(c.config.Address, "/"), path)
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+	req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+	case http.StatusUnauthorized:
+		return nil, tfe.ErrUnauthorized
+	case http.StatusNotFound:
+		return nil, tfe.ErrResourceNotFound
+	default:
+		return nil, fmt.Errorf("error requesting %s: %w", path, decodeAPIError(resp))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// decodeAPIError returns the errors of the JSON:API error document of the
+// response, with their details, e.g. why a run can't be queued, or the
+// response status if there is none.
+func decodeAPIError(resp *http.Response) error {
+	payload := jsonapi.ErrorsPayload{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil || len(payload.Errors) == 0 {
+		return errors.New(resp.Status)
+	}
+
+	msgs := []string{}
+	for _, e := range payload.Errors {
+		msg := e.Title
+		if e.Detail != "" {
+			msg = fmt.Sprintf("%s: %s", e.Title, e.Detail)
+		}
+		msgs = append(msgs, msg)
+	}
+	return errors.New(strings.Join(msgs, ", "))
+}
+
+// singleLineError joins the lines of the go-tfe errors, which separate the
+// title and the detail of the API errors with a blank line, to show them in
+// the footer.
+func singleLineError(err error) error {
+	if err == nil || !strings.Contains(err.Error(), "\n") {
+		return err
+	}
+
+	lines := []string{}
+	for _, l := range strings.Split(err.Error(), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return errors.New(strings.Join(lines, ": "))
+}
+
+// newRetryClient wraps the HTTP client to retry the rate limited requests,
+// with the same settings go-tfe uses for its own requests.
+func newRetryClient(httpClient *http.Client) *retryablehttp.Client {
+	return &retryablehttp.Client{
+		HTTPClient:   httpClient,
+		CheckRetry:   retryRateLimited,
+		Backoff:      rateLimitBackoff,
+		ErrorHandler: retryablehttp.PassthroughErrorHandler,
+		RetryWaitMin: 100 * time.Millisecond,
+		RetryWaitMax: 400 * time.Millisecond,
+		RetryMax:     30,
+	}
+}
+
+// retryRateLimited retries the requests rejected by the API rate limit, unless
+// the context is done.
+func retryRateLimited(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.StatusCode == http.StatusTooManyRequests, nil
+}
+
+// rateLimitBackoff waits until the rate limit resets, as told by the
+// X-RateLimit-Reset header, falling back to a linear backoff with jitter.
+func rateLimitBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		reset, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset"), 64)
+		if err == nil && reset > 0 {
+			return min + time.Duration(reset*float64(time.Second))
+		}
+	}
+	return retryablehttp.LinearJitterBackoff(700*time.Millisecond, 900*time.Millisecond, attemptNum, resp)
+}
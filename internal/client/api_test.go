@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAPIRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/organizations/acme/projects":
+			requests++
+			if requests == 1 {
+				w.Header().Set("X-RateLimit-Reset", "0.01")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, projectsPage)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL, "TFE_TOKEN": "test-token"}, "")
+
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	names, err := c.(*TFEClientImpl).listProjectNames(context.Background(), "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "platform", names["prj-platform"])
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const DefaultHostname = "app.terraform.io"
+
+const credentialsFile = ".terraform.d/credentials.tfrc.json"
+
+// Credentials holds the address of the TFE API and the token used to access it.
+type Credentials struct {
+	Address  string
+	Hostname string
+	Token    string
+}
+
+type credentialsFileContent struct {
+	Credentials map[string]struct {
+		Token string `json:"token"`
+	} `json:"credentials"`
+}
+
+// ResolveCredentials finds the address and token to use, following the same
+// rules as the terraform CLI.
+//
+// The address comes from TFE_ADDRESS, then from the given hostname and
+// finally defaults to app.terraform.io, unless preferHostname is set, e.g. for
+// the hostname of a profile, and then the hostname wins over TFE_ADDRESS.
+// When tokenEnv is set the token is read from that environment variable only,
+// otherwise it comes from TFE_TOKEN, then from the TF_TOKEN_<host> environment
+// variable and finally from the ~/.terraform.d/credentials.tfrc.json file. The
+// hostname preferred over TFE_ADDRESS reads the token of the host first, and
+// TFE_TOKEN only if it is the TFE_ADDRESS, or default, host, so the token is
+// not sent to another host.
+func ResolveCredentials(hostname, tokenEnv string, preferHostname bool) (*Credentials, error) {
+	address, err := resolveAddress(hostname, preferHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	c := &Credentials{
+		Address:  address,
+		Hostname: u.Host,
+	}
+
+	token, err := resolveToken(c.Hostname, tokenEnv, preferHostname && hostname != "")
+	if err != nil {
+		return nil, err
+	}
+	c.Token = token
+
+	return c, nil
+}
+
+func resolveAddress(hostname string, preferHostname bool) (string, error) {
+	address := os.Getenv("TFE_ADDRESS")
+	if address == "" || (preferHostname && hostname != "") {
+		address = hostname
+	}
+	if address == "" {
+		address = DefaultHostname
+	}
+
+	if !strings.Contains(address, "://") {
+		address = fmt.Sprintf("https://%s", address)
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+
+	return strings.TrimRight(address, "/"), nil
+}
+
+func resolveToken(hostname, tokenEnv string, chosenHostname bool) (string, error) {
+	if tokenEnv != "" {
+		token := os.Getenv(tokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("no API token found for %s: %s is not set", hostname, tokenEnv)
+		}
+		return token, nil
+	}
+
+	tfeToken := os.Getenv("TFE_TOKEN")
+	if chosenHostname && hostname != envHostname() {
+		tfeToken = ""
+	}
+	if tfeToken != "" && !chosenHostname {
+		return tfeToken, nil
+	}
+
+	if token := os.Getenv(tokenEnvName(hostname)); token != "" {
+		return token, nil
+	}
+
+	token, err := readCredentialsFile(hostname)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return token, nil
+	}
+	if tfeToken != "" {
+		return tfeToken, nil
+	}
+
+	if chosenHostname && hostname != envHostname() {
+		return "", fmt.Errorf("no API token found for %s: set %s or run terraform login", hostname, tokenEnvName(hostname))
+	}
+	return "", fmt.Errorf("no API token found for %s: set TFE_TOKEN or run terraform login", hostname)
+}
+
+// envHostname returns the host TFE_TOKEN is meant for, the one of TFE_ADDRESS
+// or app.terraform.io.
+func envHostname() string {
+	address, err := resolveAddress("", false)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// tokenEnvName returns the TF_TOKEN_ variable name for a hostname, where dots
+// are encoded as underscores and dashes as double underscores.
+func tokenEnvName(hostname string) string {
+	name := strings.ReplaceAll(hostname, "-", "__")
+	name = strings.ReplaceAll(name, ".", "_")
+	return fmt.Sprintf("TF_TOKEN_%s", name)
+}
+
+func readCredentialsFile(hostname string) (string, error) {
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+
+	path := filepath.Join(userHome, credentialsFile)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	c := credentialsFileContent{}
+	if err := json.Unmarshal(content, &c); err != nil {
+		return "", fmt.Errorf("invalid credentials file %s: %w", path, err)
+	}
+
+	return c.Credentials[hostname].Token, nil
+}
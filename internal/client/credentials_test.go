@@ -0,0 +1,273 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCredentials(t *testing.T) {
+	tests := []struct {
+		name             string
+		hostname         string
+		preferHostname   bool
+		tokenEnv         string
+		env              map[string]string
+		credentials      string
+		expectedAddress  string
+		expectedHostname string
+		expectedToken    string
+		expectedErr      bool
+	}{
+		{
+			name:             "defaults to terraform cloud",
+			env:              map[string]string{"TFE_TOKEN": "token"},
+			expectedAddress:  "https://app.terraform.io",
+			expectedHostname: "app.terraform.io",
+			expectedToken:    "token",
+		},
+		{
+			name:             "configured hostname",
+			hostname:         "tfe.example.com",
+			env:              map[string]string{"TFE_TOKEN": "token"},
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "token",
+		},
+		{
+			name:     "TFE_ADDRESS wins over the configured hostname",
+			hostname: "tfe.example.com",
+			env: map[string]string{
+				"TFE_ADDRESS": "https://other.example.com/",
+				"TFE_TOKEN":   "token",
+			},
+			expectedAddress:  "https://other.example.com",
+			expectedHostname: "other.example.com",
+			expectedToken:    "token",
+		},
+		{
+			name:           "the profile hostname wins over TFE_ADDRESS",
+			hostname:       "tfe.example.com",
+			preferHostname: true,
+			env: map[string]string{
+				"TFE_ADDRESS":              "https://other.example.com/",
+				"TF_TOKEN_tfe_example_com": "host-token",
+			},
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "host-token",
+		},
+		{
+			name:           "the profile hostname token wins over TFE_TOKEN",
+			hostname:       "tfe.example.com",
+			preferHostname: true,
+			env: map[string]string{
+				"TFE_TOKEN":                "token",
+				"TF_TOKEN_tfe_example_com": "host-token",
+			},
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "host-token",
+		},
+		{
+			name:             "the profile hostname credentials file wins over TFE_TOKEN",
+			hostname:         "tfe.example.com",
+			preferHostname:   true,
+			env:              map[string]string{"TFE_TOKEN": "token"},
+			credentials:      `{"credentials": {"tfe.example.com": {"token": "file-token"}}}`,
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "file-token",
+		},
+		{
+			name:           "TFE_TOKEN is not sent to another profile hostname",
+			hostname:       "tfe.example.com",
+			preferHostname: true,
+			env:            map[string]string{"TFE_TOKEN": "token"},
+			expectedErr:    true,
+		},
+		{
+			name:           "TFE_TOKEN is used by the profile hostname of TFE_ADDRESS",
+			hostname:       "tfe.example.com",
+			preferHostname: true,
+			env: map[string]string{
+				"TFE_ADDRESS": "https://tfe.example.com",
+				"TFE_TOKEN":   "token",
+			},
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "token",
+		},
+		{
+			name:             "TFE_TOKEN is used by the default profile hostname",
+			hostname:         "app.terraform.io",
+			preferHostname:   true,
+			env:              map[string]string{"TFE_TOKEN": "token"},
+			expectedAddress:  "https://app.terraform.io",
+			expectedHostname: "app.terraform.io",
+			expectedToken:    "token",
+		},
+		{
+			name:           "TFE_ADDRESS is used by the profiles without hostname",
+			preferHostname: true,
+			env: map[string]string{
+				"TFE_ADDRESS": "https://other.example.com/",
+				"TFE_TOKEN":   "token",
+			},
+			expectedAddress:  "https://other.example.com",
+			expectedHostname: "other.example.com",
+			expectedToken:    "token",
+		},
+		{
+			name:     "token from TF_TOKEN_ variable",
+			hostname: "my-tfe.example.com",
+			env: map[string]string{
+				"TF_TOKEN_my__tfe_example_com": "host-token",
+			},
+			expectedAddress:  "https://my-tfe.example.com",
+			expectedHostname: "my-tfe.example.com",
+			expectedToken:    "host-token",
+		},
+		{
+			name:             "token from credentials file",
+			hostname:         "tfe.example.com",
+			credentials:      `{"credentials": {"tfe.example.com": {"token": "file-token"}}}`,
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "file-token",
+		},
+		{
+			name:     "TFE_TOKEN wins over the other sources",
+			hostname: "tfe.example.com",
+			env: map[string]string{
+				"TFE_TOKEN":                "token",
+				"TF_TOKEN_tfe_example_com": "host-token",
+			},
+			credentials:      `{"credentials": {"tfe.example.com": {"token": "file-token"}}}`,
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "token",
+		},
+		{
+			name:     "token from a profile variable",
+			hostname: "tfe.example.com",
+			tokenEnv: "MY_TFE_TOKEN",
+			env: map[string]string{
+				"TFE_TOKEN":    "token",
+				"MY_TFE_TOKEN": "profile-token",
+			},
+			expectedAddress:  "https://tfe.example.com",
+			expectedHostname: "tfe.example.com",
+			expectedToken:    "profile-token",
+		},
+		{
+			name:        "empty profile variable",
+			hostname:    "tfe.example.com",
+			tokenEnv:    "MY_TFE_TOKEN",
+			env:         map[string]string{"TFE_TOKEN": "token"},
+			expectedErr: true,
+		},
+		{
+			name:        "credentials for another host",
+			hostname:    "tfe.example.com",
+			credentials: `{"credentials": {"app.terraform.io": {"token": "file-token"}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid credentials file",
+			credentials: `{"credentials": `,
+			expectedErr: true,
+		},
+		{
+			name:        "no token",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setupCredentialsEnv(t, tc.env, tc.credentials)
+
+			c, err := ResolveCredentials(tc.hostname, tc.tokenEnv, tc.preferHostname)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAddress, c.Address)
+			assert.Equal(t, tc.expectedHostname, c.Hostname)
+			assert.Equal(t, tc.expectedToken, c.Token)
+		})
+	}
+}
+
+func TestNewTFEClient(t *testing.T) {
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/organizations":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprint(w, `{"data": [{"id": "my-org", "type": "organizations", "attributes": {"name": "my-org"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	credentials := fmt.Sprintf(`{"credentials": {"%s": {"token": "file-token"}}}`, u.Host)
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL}, credentials)
+
+	c, err := NewTFEClient(Options{Hostname: "ignored.example.com"})
+	require.NoError(t, err)
+
+	orgs, err := c.ListOrganizations(context.Background(), -1)
+	require.NoError(t, err)
+	require.Len(t, orgs.Items, 1)
+	assert.Equal(t, "my-org", orgs.Items[0].Name)
+
+	for _, a := range authorizations {
+		assert.Equal(t, "Bearer file-token", a)
+	}
+}
+
+// setupCredentialsEnv isolates the test from the user's environment and
+// terraform credentials.
+func setupCredentialsEnv(t *testing.T, env map[string]string, credentials string) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, e := range os.Environ() {
+		name, _, _ := strings.Cut(e, "=")
+		if name == "TFE_ADDRESS" || name == "TFE_TOKEN" || strings.HasPrefix(name, "TF_TOKEN_") {
+			t.Setenv(name, "")
+		}
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	if credentials == "" {
+		return
+	}
+
+	path := filepath.Join(home, credentialsFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(credentials), 0600))
+}
@@ -0,0 +1,1037 @@
+// Package fake provides an in-memory implementation of client.TFEClient to be
+// used in tests.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const pageSize = 30
+
+// currentUser is the user the client is authenticated as, holding the locks
+// of the workspaces locked with LockWorkspace.
+const currentUser = "jdoe"
+
+var _ client.TFEClient = &Client{}
+
+// Client is an in-memory TFE client. Use the Add* and Set* methods to load
+// fixtures and FailWith to make a method return an error.
+type Client struct {
+	mu sync.Mutex
+
+	organizations []*tfe.Organization
+	workspaces    map[string][]*tfe.Workspace
+	variables     map[string][]*tfe.Variable
+	variableSets  map[string][]*tfe.VariableSet
+	runs          map[string][]*tfe.Run
+	accesses      map[string][]*tfe.TeamAccess
+	plans         map[string]*tfe.Plan
+	planJSONs     map[string]*client.PlanJSON
+	stateVersions map[string][]*client.StateVersion
+	states        map[string]*client.State
+	outputs       map[string][]*tfe.StateVersionOutput
+	runOptions    map[string]client.RunOptions
+	locks         map[string]*client.WorkspaceLock
+	lockReasons   map[string]string
+	variableSeq   int
+	planLogs      map[string]string
+	applyLogs     map[string]string
+	logReaders    map[string]io.Reader
+	errors        map[string]error
+}
+
+func NewClient() *Client {
+	return &Client{
+		workspaces:    map[string][]*tfe.Workspace{},
+		variables:     map[string][]*tfe.Variable{},
+		variableSets:  map[string][]*tfe.VariableSet{},
+		runs:          map[string][]*tfe.Run{},
+		accesses:      map[string][]*tfe.TeamAccess{},
+		plans:         map[string]*tfe.Plan{},
+		planJSONs:     map[string]*client.PlanJSON{},
+		stateVersions: map[string][]*client.StateVersion{},
+		states:        map[string]*client.State{},
+		outputs:       map[string][]*tfe.StateVersionOutput{},
+		runOptions:    map[string]client.RunOptions{},
+		locks:         map[string]*client.WorkspaceLock{},
+		lockReasons:   map[string]string{},
+		planLogs:      map[string]string{},
+		applyLogs:     map[string]string{},
+		logReaders:    map[string]io.Reader{},
+		errors:        map[string]error{},
+	}
+}
+
+// NewOrganization builds an organization fixture.
+func NewOrganization(name string) *tfe.Organization {
+	return &tfe.Organization{
+		ExternalID: fmt.Sprintf("org-%s", name),
+		Name:       name,
+		Email:      fmt.Sprintf("admin@%s.example.com", name),
+	}
+}
+
+// NewWorkspace builds a workspace fixture.
+func NewWorkspace(id, name string) *tfe.Workspace {
+	return &tfe.Workspace{
+		ID:               id,
+		Name:             name,
+		TerraformVersion: "1.1.9",
+		ExecutionMode:    "remote",
+		UpdatedAt:        time.Now().Add(-time.Hour),
+		TagNames:         []string{},
+		Permissions: &tfe.WorkspacePermissions{
+			CanLock:        true,
+			CanUnlock:      true,
+			CanForceUnlock: true,
+		},
+	}
+}
+
+// NewUserLock builds the lock of a workspace locked by the user.
+func NewUserLock(username string) *client.WorkspaceLock {
+	return &client.WorkspaceLock{
+		HolderID:   fmt.Sprintf("user-%s", username),
+		HolderType: "users",
+		HolderName: username,
+	}
+}
+
+// NewVariable builds a terraform variable fixture.
+func NewVariable(id, key, value string) *tfe.Variable {
+	return &tfe.Variable{
+		ID:       id,
+		Key:      key,
+		Value:    value,
+		Category: tfe.CategoryTerraform,
+	}
+}
+
+// NewVariableSet builds a variable set fixture, applied to no workspace and
+// without variables.
+func NewVariableSet(id, name string, global bool) *tfe.VariableSet {
+	return &tfe.VariableSet{
+		ID:         id,
+		Name:       name,
+		Global:     global,
+		Workspaces: []*tfe.Workspace{},
+		Variables:  []*tfe.VariableSetVariable{},
+	}
+}
+
+// NewVariableSetVariable builds a terraform variable fixture of a variable
+// set.
+func NewVariableSetVariable(id, key, value string) *tfe.VariableSetVariable {
+	return &tfe.VariableSetVariable{
+		ID:       id,
+		Key:      key,
+		Value:    value,
+		Category: tfe.CategoryTerraform,
+	}
+}
+
+// NewRun builds a run fixture with its plan and apply.
+func NewRun(id, message string, status tfe.RunStatus) *tfe.Run {
+	return &tfe.Run{
+		ID:        id,
+		Message:   message,
+		Status:    status,
+		Actions:   runActions(status),
+		Source:    tfe.RunSourceUI,
+		CreatedAt: time.Now().Add(-time.Minute),
+		CreatedBy: &tfe.User{Username: "jdoe"},
+		Plan:      &tfe.Plan{ID: fmt.Sprintf("plan-%s", id), Status: tfe.PlanFinished},
+		Apply:     &tfe.Apply{ID: fmt.Sprintf("apply-%s", id)},
+	}
+}
+
+// runActions returns the actions allowed on a run in the status. A run is
+// only force cancelable a while after being canceled, so the fixtures set it.
+func runActions(status tfe.RunStatus) *tfe.RunActions {
+	switch status {
+	case tfe.RunPlanned, tfe.RunCostEstimated, tfe.RunPolicyChecked, tfe.RunPolicyOverride:
+		return &tfe.RunActions{IsConfirmable: true, IsDiscardable: true}
+	case tfe.RunPending, tfe.RunPlanQueued, tfe.RunPlanning, tfe.RunApplyQueued, tfe.RunApplying, tfe.RunConfirmed:
+		return &tfe.RunActions{IsCancelable: true}
+	}
+	return &tfe.RunActions{}
+}
+
+// NewTeamAccess builds a team access fixture.
+func NewTeamAccess(team string, access tfe.AccessType) *tfe.TeamAccess {
+	return &tfe.TeamAccess{
+		ID:     fmt.Sprintf("tws-%s", team),
+		Access: access,
+		Team:   &tfe.Team{ID: fmt.Sprintf("team-%s", team), Name: team},
+	}
+}
+
+// NewOutput builds a state version output fixture.
+func NewOutput(name, outputType string, value interface{}, sensitive bool) *tfe.StateVersionOutput {
+	return &tfe.StateVersionOutput{
+		ID:        fmt.Sprintf("wsout-%s", name),
+		Name:      name,
+		Type:      outputType,
+		Value:     value,
+		Sensitive: sensitive,
+	}
+}
+
+func (c *Client) AddOrganization(org *tfe.Organization) *tfe.Organization {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.organizations = append(c.organizations, org)
+	return org
+}
+
+func (c *Client) AddWorkspace(org string, w *tfe.Workspace) *tfe.Workspace {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.Organization = &tfe.Organization{Name: org}
+	c.workspaces[org] = append(c.workspaces[org], w)
+	return w
+}
+
+func (c *Client) AddVariable(workspaceID string, v *tfe.Variable) *tfe.Variable {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v.Workspace = &tfe.Workspace{ID: workspaceID}
+	c.variables[workspaceID] = append(c.variables[workspaceID], v)
+	return v
+}
+
+// AddVariableSet adds a variable set to the organization, set its workspaces
+// and variables before adding it.
+func (c *Client) AddVariableSet(org string, s *tfe.VariableSet) *tfe.VariableSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s.Organization = &tfe.Organization{Name: org}
+	for _, v := range s.Variables {
+		v.VariableSet = &tfe.VariableSet{ID: s.ID}
+	}
+	c.variableSets[org] = append(c.variableSets[org], s)
+	return s
+}
+
+// AddRun adds a run to the workspace, the most recent run added becomes the
+// workspace current run.
+func (c *Client) AddRun(workspaceID string, r *tfe.Run) *tfe.Run {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.addRun(workspaceID, r)
+}
+
+func (c *Client) addRun(workspaceID string, r *tfe.Run) *tfe.Run {
+	r.Workspace = &tfe.Workspace{ID: workspaceID}
+	c.runs[workspaceID] = append([]*tfe.Run{r}, c.runs[workspaceID]...)
+	if r.Plan != nil {
+		c.plans[r.Plan.ID] = r.Plan
+	}
+
+	for _, ws := range c.workspaces {
+		for j, w := range ws {
+			if w.ID == workspaceID {
+				workspace := *w
+				workspace.CurrentRun = r
+				ws[j] = &workspace
+			}
+		}
+	}
+	return r
+}
+
+// SetRunStatus changes the status of a run, e.g. to simulate a run
+// progressing. The run is replaced by a copy so the values already returned
+// are not changed.
+func (c *Client) SetRunStatus(runID string, status tfe.RunStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setRunStatus(runID, status)
+}
+
+func (c *Client) setRunStatus(runID string, status tfe.RunStatus) {
+	for workspaceID, runs := range c.runs {
+		for i, r := range runs {
+			if r.ID != runID {
+				continue
+			}
+
+			updated := *r
+			updated.Status = status
+			updated.Actions = runActions(status)
+			c.runs[workspaceID][i] = &updated
+
+			for _, ws := range c.workspaces {
+				for j, w := range ws {
+					if w.CurrentRun == r {
+						workspace := *w
+						workspace.CurrentRun = &updated
+						ws[j] = &workspace
+					}
+				}
+			}
+		}
+	}
+}
+
+// SetWorkspaceLock locks the workspace by the lock holder, or unlocks it if
+// the lock is nil. The workspace is replaced by a copy so the values already
+// returned are not changed.
+func (c *Client) SetWorkspaceLock(workspaceID string, lock *client.WorkspaceLock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setWorkspaceLock(workspaceID, lock)
+}
+
+func (c *Client) setWorkspaceLock(workspaceID string, lock *client.WorkspaceLock) {
+	if lock == nil {
+		delete(c.locks, workspaceID)
+	} else {
+		c.locks[workspaceID] = lock
+	}
+
+	for _, ws := range c.workspaces {
+		for j, w := range ws {
+			if w.ID == workspaceID {
+				workspace := *w
+				workspace.Locked = lock != nil
+				ws[j] = &workspace
+			}
+		}
+	}
+}
+
+func (c *Client) AddTeamAccess(workspaceID string, a *tfe.TeamAccess) *tfe.TeamAccess {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	a.Workspace = &tfe.Workspace{ID: workspaceID}
+	c.accesses[workspaceID] = append(c.accesses[workspaceID], a)
+	return a
+}
+
+// NewStateVersion builds a state version fixture with the resources of the
+// state.
+func NewStateVersion(id string, state *client.State) *client.StateVersion {
+	sv := &client.StateVersion{
+		ID:                 id,
+		Serial:             state.Serial,
+		CreatedAt:          time.Now().Add(-time.Hour),
+		DownloadURL:        fmt.Sprintf("https://archivist.terraform.io/v1/object/%s", id),
+		ResourcesProcessed: true,
+	}
+	for _, r := range state.Resources {
+		sv.Resources = append(sv.Resources, client.StateVersionResource{
+			Name:   r.Name,
+			Type:   r.Type,
+			Count:  len(r.Instances),
+			Module: r.Module,
+		})
+	}
+	return sv
+}
+
+// AddStateVersion adds a state version of the workspace with its state, the
+// most recent state version added is listed first.
+func (c *Client) AddStateVersion(workspaceID string, sv *client.StateVersion, state *client.State) *client.StateVersion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateVersions[workspaceID] = append([]*client.StateVersion{sv}, c.stateVersions[workspaceID]...)
+	c.states[sv.DownloadURL] = state
+	return sv
+}
+
+// AddOutput adds an output to the current state version of the workspace.
+func (c *Client) AddOutput(workspaceID string, o *tfe.StateVersionOutput) *tfe.StateVersionOutput {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.outputs[workspaceID] = append(c.outputs[workspaceID], o)
+	return o
+}
+
+// SetPlanJSON sets the JSON execution plan of the plan.
+func (c *Client) SetPlanJSON(planID string, plan *client.PlanJSON) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.planJSONs[planID] = plan
+}
+
+func (c *Client) SetPlanLogs(planID, logs string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.planLogs[planID] = logs
+}
+
+func (c *Client) SetApplyLogs(applyID, logs string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.applyLogs[applyID] = logs
+}
+
+// SetLogsReader makes the plan or apply logs be read from r, e.g. an
+// io.Pipe to simulate logs arriving while the run is in progress.
+func (c *Client) SetLogsReader(planOrApplyID string, r io.Reader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logReaders[planOrApplyID] = r
+}
+
+// FailWith makes the given method, e.g. "ReadWorkspace", return err.
+func (c *Client) FailWith(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errors[method] = err
+}
+
+func (c *Client) fail(ctx context.Context, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.errors[method]
+}
+
+func (c *Client) ListOrganizations(ctx context.Context, pageNumber int) (*tfe.OrganizationList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListOrganizations"); err != nil {
+		return nil, err
+	}
+
+	from, to, pagination := paginate(len(c.organizations), pageNumber)
+	return &tfe.OrganizationList{
+		Pagination: pagination,
+		Items:      c.organizations[from:to],
+	}, nil
+}
+
+// ListWorkspaces returns the workspaces matching the search, a
+// client.WorkspaceQuery.
+func (c *Client) ListWorkspaces(ctx context.Context, org string, searchText string, pageNumber int) (*tfe.WorkspaceList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaces"); err != nil {
+		return nil, err
+	}
+
+	query, err := client.ParseWorkspaceQuery(searchText)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []*tfe.Workspace{}
+	for _, w := range c.workspaces[org] {
+		if query.Matches(w) {
+			found = append(found, w)
+		}
+	}
+
+	from, to, pagination := paginate(len(found), pageNumber)
+	return &tfe.WorkspaceList{
+		Pagination: pagination,
+		Items:      found[from:to],
+	}, nil
+}
+
+func (c *Client) ReadWorkspace(ctx context.Context, org, workspace string) (*tfe.Workspace, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspace"); err != nil {
+		return nil, err
+	}
+
+	for _, w := range c.workspaces[org] {
+		if w.Name == workspace {
+			return w, nil
+		}
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+func (c *Client) ListWorkspaceVariables(ctx context.Context, workspaceID string, pageNumber int) (*tfe.VariableList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceVariables"); err != nil {
+		return nil, err
+	}
+
+	from, to, pagination := paginate(len(c.variables[workspaceID]), pageNumber)
+	return &tfe.VariableList{
+		Pagination: pagination,
+		Items:      c.variables[workspaceID][from:to],
+	}, nil
+}
+
+func (c *Client) ListVariableSets(ctx context.Context, org string, pageNumber int) (*tfe.VariableSetList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListVariableSets"); err != nil {
+		return nil, err
+	}
+
+	from, to, pagination := paginate(len(c.variableSets[org]), pageNumber)
+	return &tfe.VariableSetList{
+		Pagination: pagination,
+		Items:      c.variableSets[org][from:to],
+	}, nil
+}
+
+func (c *Client) ListVariableSetVariables(ctx context.Context, variableSetID string, pageNumber int) (*tfe.VariableSetVariableList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListVariableSetVariables"); err != nil {
+		return nil, err
+	}
+
+	for _, sets := range c.variableSets {
+		for _, s := range sets {
+			if s.ID == variableSetID {
+				from, to, pagination := paginate(len(s.Variables), pageNumber)
+				return &tfe.VariableSetVariableList{
+					Pagination: pagination,
+					Items:      s.Variables[from:to],
+				}, nil
+			}
+		}
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+func (c *Client) ListWorkspaceVariableSets(ctx context.Context, workspaceID string) ([]*tfe.VariableSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceVariableSets"); err != nil {
+		return nil, err
+	}
+
+	applied := []*tfe.VariableSet{}
+	for org, workspaces := range c.workspaces {
+		for _, w := range workspaces {
+			if w.ID != workspaceID {
+				continue
+			}
+			for _, s := range c.variableSets[org] {
+				if client.VariableSetApplied(s, workspaceID) {
+					applied = append(applied, s)
+				}
+			}
+		}
+	}
+	return applied, nil
+}
+
+func (c *Client) ListWorkspaceRuns(ctx context.Context, workspaceID string) (*tfe.RunList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceRuns"); err != nil {
+		return nil, err
+	}
+
+	from, to, pagination := paginate(len(c.runs[workspaceID]), -1)
+	return &tfe.RunList{
+		Pagination: pagination,
+		Items:      c.runs[workspaceID][from:to],
+	}, nil
+}
+
+func (c *Client) ListWorkspaceTeamAccesses(ctx context.Context, workspaceID string) (*tfe.TeamAccessList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceTeamAccesses"); err != nil {
+		return nil, err
+	}
+
+	from, to, pagination := paginate(len(c.accesses[workspaceID]), -1)
+	return &tfe.TeamAccessList{
+		Pagination: pagination,
+		Items:      c.accesses[workspaceID][from:to],
+	}, nil
+}
+
+func (c *Client) ReadWorkspaceRun(ctx context.Context, runID string) (*tfe.Run, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspaceRun"); err != nil {
+		return nil, err
+	}
+
+	r, err := c.findRun(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the API includes the workspace of the run
+	run := *r
+	for _, ws := range c.workspaces {
+		for _, w := range ws {
+			if w.ID == r.Workspace.ID {
+				run.Workspace = w
+			}
+		}
+	}
+	return &run, nil
+}
+
+func (c *Client) ReadWorkspacePlan(ctx context.Context, planID string) (*tfe.Plan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspacePlan"); err != nil {
+		return nil, err
+	}
+
+	p, ok := c.plans[planID]
+	if !ok {
+		return nil, tfe.ErrResourceNotFound
+	}
+	return p, nil
+}
+
+func (c *Client) ReadWorkspacePlanJSONOutput(ctx context.Context, planID string) (*client.PlanJSON, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspacePlanJSONOutput"); err != nil {
+		return nil, err
+	}
+
+	p, ok := c.planJSONs[planID]
+	if !ok {
+		return nil, tfe.ErrResourceNotFound
+	}
+	return p, nil
+}
+
+func (c *Client) ReadWorkspacePlanLogs(ctx context.Context, planID string) (io.Reader, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspacePlanLogs"); err != nil {
+		return nil, err
+	}
+
+	if r, ok := c.logReaders[planID]; ok {
+		return r, nil
+	}
+	return strings.NewReader(c.planLogs[planID]), nil
+}
+
+func (c *Client) ReadWorkspaceApplyLogs(ctx context.Context, applyID string) (io.Reader, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspaceApplyLogs"); err != nil {
+		return nil, err
+	}
+
+	if r, ok := c.logReaders[applyID]; ok {
+		return r, nil
+	}
+	return strings.NewReader(c.applyLogs[applyID]), nil
+}
+
+func (c *Client) ListWorkspaceStateVersions(ctx context.Context, org, workspace string, pageNumber int) (*client.StateVersionList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceStateVersions"); err != nil {
+		return nil, err
+	}
+
+	for _, w := range c.workspaces[org] {
+		if w.Name != workspace {
+			continue
+		}
+
+		versions := c.stateVersions[w.ID]
+		from, to, pagination := paginate(len(versions), pageNumber)
+		return &client.StateVersionList{
+			Pagination: pagination,
+			Items:      versions[from:to],
+		}, nil
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+func (c *Client) ReadCurrentStateVersion(ctx context.Context, workspaceID string) (*client.StateVersion, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadCurrentStateVersion"); err != nil {
+		return nil, err
+	}
+
+	versions := c.stateVersions[workspaceID]
+	if len(versions) == 0 {
+		return nil, tfe.ErrResourceNotFound
+	}
+	return versions[0], nil
+}
+
+func (c *Client) DownloadStateVersion(ctx context.Context, downloadURL string) (*client.State, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "DownloadStateVersion"); err != nil {
+		return nil, err
+	}
+
+	state, ok := c.states[downloadURL]
+	if !ok {
+		return nil, tfe.ErrResourceNotFound
+	}
+	return state, nil
+}
+
+func (c *Client) ListWorkspaceOutputs(ctx context.Context, workspaceID string) ([]*tfe.StateVersionOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ListWorkspaceOutputs"); err != nil {
+		return nil, err
+	}
+
+	outputs := []*tfe.StateVersionOutput{}
+	for _, o := range c.outputs[workspaceID] {
+		if o.Sensitive {
+			masked := *o
+			masked.Value = nil
+			o = &masked
+		}
+		outputs = append(outputs, o)
+	}
+	return outputs, nil
+}
+
+// ReadWorkspaceOutput returns the output with its value, like the API, which
+// only returns the sensitive values when reading the outputs one by one.
+func (c *Client) ReadWorkspaceOutput(ctx context.Context, outputID string) (*tfe.StateVersionOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspaceOutput"); err != nil {
+		return nil, err
+	}
+
+	for _, outputs := range c.outputs {
+		for _, o := range outputs {
+			if o.ID == outputID {
+				return o, nil
+			}
+		}
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+// RunOptions returns the options of a run created with CreateRun.
+func (c *Client) RunOptions(runID string) client.RunOptions {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.runOptions[runID]
+}
+
+func (c *Client) CreateRun(ctx context.Context, workspaceID string, options client.RunOptions) (*tfe.Run, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "CreateRun"); err != nil {
+		return nil, err
+	}
+	if _, ok := c.runs[workspaceID]; !ok {
+		return nil, tfe.ErrResourceNotFound
+	}
+
+	r := NewRun(fmt.Sprintf("run-queued-%d", len(c.runOptions)+1), options.Message, tfe.RunPending)
+	r.CreatedAt = time.Now()
+	r.IsDestroy = options.IsDestroy
+	r.RefreshOnly = options.RefreshOnly
+	r.TargetAddrs = options.Targets
+	r.ReplaceAddrs = options.Replaces
+	c.runOptions[r.ID] = options
+
+	return c.addRun(workspaceID, r), nil
+}
+
+func (c *Client) ApplyRun(ctx context.Context, runID string) error {
+	return c.runAction(ctx, "ApplyRun", runID, func(a *tfe.RunActions) bool { return a.IsConfirmable }, tfe.RunConfirmed)
+}
+
+func (c *Client) DiscardRun(ctx context.Context, runID string) error {
+	return c.runAction(ctx, "DiscardRun", runID, func(a *tfe.RunActions) bool { return a.IsDiscardable }, tfe.RunDiscarded)
+}
+
+func (c *Client) CancelRun(ctx context.Context, runID string) error {
+	return c.runAction(ctx, "CancelRun", runID, func(a *tfe.RunActions) bool { return a.IsCancelable }, tfe.RunCanceled)
+}
+
+func (c *Client) ForceCancelRun(ctx context.Context, runID string) error {
+	return c.runAction(ctx, "ForceCancelRun", runID, func(a *tfe.RunActions) bool { return a.IsForceCancelable }, tfe.RunCanceled)
+}
+
+// runAction changes the run to the status if the action is allowed, failing
+// like the API does otherwise.
+func (c *Client) runAction(ctx context.Context, method, runID string, allowed func(*tfe.RunActions) bool, status tfe.RunStatus) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, method); err != nil {
+		return err
+	}
+
+	r, err := c.findRun(runID)
+	if err != nil {
+		return err
+	}
+	if r.Actions == nil || !allowed(r.Actions) {
+		return errors.New("transition not allowed")
+	}
+
+	c.setRunStatus(runID, status)
+	return nil
+}
+
+func (c *Client) findRun(runID string) (*tfe.Run, error) {
+	workspaceIDs := []string{}
+	for id := range c.runs {
+		workspaceIDs = append(workspaceIDs, id)
+	}
+	sort.Strings(workspaceIDs)
+
+	for _, id := range workspaceIDs {
+		for _, r := range c.runs[id] {
+			if r.ID == runID {
+				return r, nil
+			}
+		}
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+func (c *Client) ReadWorkspaceLock(ctx context.Context, workspaceID string) (*client.WorkspaceLock, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ReadWorkspaceLock"); err != nil {
+		return nil, err
+	}
+
+	return c.locks[workspaceID], nil
+}
+
+// LockReason returns the reason of the last lock of the workspace with
+// LockWorkspace.
+func (c *Client) LockReason(workspaceID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lockReasons[workspaceID]
+}
+
+func (c *Client) LockWorkspace(ctx context.Context, workspaceID, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "LockWorkspace"); err != nil {
+		return err
+	}
+	if c.locks[workspaceID] != nil {
+		return tfe.ErrWorkspaceLocked
+	}
+
+	c.lockReasons[workspaceID] = reason
+	c.setWorkspaceLock(workspaceID, NewUserLock(currentUser))
+	return nil
+}
+
+// UnlockWorkspace fails like the API does if the workspace is locked by a run
+// or by another user.
+func (c *Client) UnlockWorkspace(ctx context.Context, workspaceID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "UnlockWorkspace"); err != nil {
+		return err
+	}
+
+	lock := c.locks[workspaceID]
+	switch {
+	case lock == nil:
+		return tfe.ErrWorkspaceNotLocked
+	case lock.HolderType == "runs":
+		return tfe.ErrWorkspaceLockedByRun
+	case lock.HolderID != NewUserLock(currentUser).HolderID:
+		return errors.New("workspace locked by another user")
+	}
+
+	c.setWorkspaceLock(workspaceID, nil)
+	return nil
+}
+
+func (c *Client) ForceUnlockWorkspace(ctx context.Context, workspaceID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "ForceUnlockWorkspace"); err != nil {
+		return err
+	}
+	if c.locks[workspaceID] == nil {
+		return tfe.ErrWorkspaceNotLocked
+	}
+
+	c.setWorkspaceLock(workspaceID, nil)
+	return nil
+}
+
+func (c *Client) CreateVariable(ctx context.Context, workspaceID string, options client.VariableOptions) (*tfe.Variable, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "CreateVariable"); err != nil {
+		return nil, err
+	}
+	if err := c.checkVariableKey(workspaceID, "", options.Key); err != nil {
+		return nil, err
+	}
+
+	c.variableSeq++
+	v := &tfe.Variable{
+		ID:        fmt.Sprintf("var-created-%d", c.variableSeq),
+		Category:  options.Category,
+		Workspace: &tfe.Workspace{ID: workspaceID},
+	}
+	setVariableOptions(v, options)
+	c.variables[workspaceID] = append(c.variables[workspaceID], v)
+
+	return v, nil
+}
+
+// UpdateVariable replaces the variable by an updated copy so the values
+// already returned are not changed.
+func (c *Client) UpdateVariable(ctx context.Context, workspaceID, variableID string, options client.VariableOptions) (*tfe.Variable, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "UpdateVariable"); err != nil {
+		return nil, err
+	}
+	if err := c.checkVariableKey(workspaceID, variableID, options.Key); err != nil {
+		return nil, err
+	}
+
+	for i, v := range c.variables[workspaceID] {
+		if v.ID != variableID {
+			continue
+		}
+		if v.Sensitive && !options.Sensitive {
+			return nil, errors.New("sensitive variables cannot be made non sensitive")
+		}
+
+		updated := *v
+		setVariableOptions(&updated, options)
+		c.variables[workspaceID][i] = &updated
+		return &updated, nil
+	}
+	return nil, tfe.ErrResourceNotFound
+}
+
+func (c *Client) DeleteVariable(ctx context.Context, workspaceID, variableID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.fail(ctx, "DeleteVariable"); err != nil {
+		return err
+	}
+
+	for i, v := range c.variables[workspaceID] {
+		if v.ID == variableID {
+			variables := append([]*tfe.Variable{}, c.variables[workspaceID][:i]...)
+			c.variables[workspaceID] = append(variables, c.variables[workspaceID][i+1:]...)
+			return nil
+		}
+	}
+	return tfe.ErrResourceNotFound
+}
+
+// checkVariableKey fails like the API does if another variable of the
+// workspace has the key.
+func (c *Client) checkVariableKey(workspaceID, variableID, key string) error {
+	for _, v := range c.variables[workspaceID] {
+		if v.ID != variableID && v.Key == key {
+			return errors.New("Key has already been taken")
+		}
+	}
+	return nil
+}
+
+func setVariableOptions(v *tfe.Variable, options client.VariableOptions) {
+	v.Key = options.Key
+	v.Description = options.Description
+	v.HCL = options.HCL
+	v.Sensitive = options.Sensitive
+	if options.Value != nil {
+		v.Value = *options.Value
+	}
+}
+
+// paginate returns the slice bounds of the requested page, where -1 is the
+// first page, like the TFE API does.
+func paginate(total, pageNumber int) (int, int, *tfe.Pagination) {
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+
+	totalPages := (total + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	from := (pageNumber - 1) * pageSize
+	if from > total {
+		from = total
+	}
+	to := from + pageSize
+	if to > total {
+		to = total
+	}
+
+	return from, to, &tfe.Pagination{
+		CurrentPage: pageNumber,
+		TotalPages:  totalPages,
+		TotalCount:  total,
+	}
+}
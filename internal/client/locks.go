@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// WorkspaceLock is the holder of a workspace lock, a user, a team or a run.
+// go-tfe does not decode the locked_by relationship, as it points to
+// different resource types, so it is read directly from the API.
+type WorkspaceLock struct {
+	HolderID   string
+	HolderType string
+	HolderName string
+}
+
+// Holder returns the name of the lock holder and its kind, e.g. "jdoe (user)".
+func (l *WorkspaceLock) Holder() string {
+	name := l.HolderName
+	if name == "" {
+		name = l.HolderID
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.TrimSuffix(l.HolderType, "s"))
+}
+
+type workspaceLockDocument struct {
+	Data struct {
+		Attributes struct {
+			Locked bool `json:"locked"`
+		} `json:"attributes"`
+		Relationships struct {
+			LockedBy struct {
+				Data *struct {
+					ID   string `json:"id"`
+					Type string `json:"type"`
+				} `json:"data"`
+			} `json:"locked-by"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Included []struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			Username string `json:"username"`
+			Name     string `json:"name"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+// ReadWorkspaceLock returns the holder of the workspace lock, nil if the
+// workspace is not locked.
+func (c *TFEClientImpl) ReadWorkspaceLock(ctx context.Context, workspaceID string) (*WorkspaceLock, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	doc := workspaceLockDocument{}
+	path := fmt.Sprintf("workspaces/%s?include=locked_by", url.PathEscape(workspaceID))
+	if err := c.getAPI(ctx, path, &doc); err != nil {
+		return nil, err
+	}
+
+	holder := doc.Data.Relationships.LockedBy.Data
+	if !doc.Data.Attributes.Locked || holder == nil {
+		return nil, nil
+	}
+
+	lock := &WorkspaceLock{HolderID: holder.ID, HolderType: holder.Type}
+	for _, i := range doc.Included {
+		if i.ID != holder.ID || i.Type != holder.Type {
+			continue
+		}
+		lock.HolderName = i.Attributes.Username
+		if lock.HolderName == "" {
+			lock.HolderName = i.Attributes.Name
+		}
+	}
+	return lock, nil
+}
+
+// LockWorkspace locks the workspace, preventing runs, with an optional reason.
+func (c *TFEClientImpl) LockWorkspace(ctx context.Context, workspaceID, reason string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	options := tfe.WorkspaceLockOptions{}
+	if reason != "" {
+		options.Reason = tfe.String(reason)
+	}
+	_, err := c.client.Workspaces.Lock(ctx, workspaceID, options)
+	return err
+}
+
+// UnlockWorkspace unlocks a workspace locked by the current user.
+func (c *TFEClientImpl) UnlockWorkspace(ctx context.Context, workspaceID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	_, err := c.client.Workspaces.Unlock(ctx, workspaceID)
+	return err
+}
+
+// ForceUnlockWorkspace unlocks a workspace locked by another user, requiring
+// the admin permission on the workspace.
+func (c *TFEClientImpl) ForceUnlockWorkspace(ctx context.Context, workspaceID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	_, err := c.client.Workspaces.ForceUnlock(ctx, workspaceID)
+	return err
+}
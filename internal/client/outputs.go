@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+const outputsPageSize = 100
+
+// ListWorkspaceOutputs returns the outputs of the current state version of
+// the workspace, none if the workspace has no state yet. The outputs list
+// does not include the sensitive values, read with ReadWorkspaceOutput only
+// when they are needed.
+func (c *TFEClientImpl) ListWorkspaceOutputs(ctx context.Context, workspaceID string) ([]*tfe.StateVersionOutput, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	sv, err := c.client.StateVersions.ReadCurrent(ctx, workspaceID)
+	if errors.Is(err, tfe.ErrResourceNotFound) {
+		return []*tfe.StateVersionOutput{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := c.client.StateVersions.ListOutputs(ctx, sv.ID, &tfe.StateVersionOutputsListOptions{
+		ListOptions: tfe.ListOptions{PageSize: outputsPageSize},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outputs.Items, nil
+}
+
+// ReadWorkspaceOutput returns the output with its value, including the
+// sensitive values.
+func (c *TFEClientImpl) ReadWorkspaceOutput(ctx context.Context, outputID string) (*tfe.StateVersionOutput, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.StateVersionOutputs.Read(ctx, outputID)
+}
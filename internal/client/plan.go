@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PlanJSON is the JSON execution plan of a run, the same as the output of
+// terraform show -json. Only the fields used by terrui are decoded.
+type PlanJSON struct {
+	FormatVersion    string            `json:"format_version"`
+	TerraformVersion string            `json:"terraform_version"`
+	ResourceChanges  []ResourceChange  `json:"resource_changes"`
+	OutputChanges    map[string]Change `json:"output_changes"`
+}
+
+// ResourceChange is the planned change of a resource instance.
+type ResourceChange struct {
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address"`
+	ModuleAddress   string `json:"module_address"`
+	Mode            string `json:"mode"`
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	ProviderName    string `json:"provider_name"`
+	ActionReason    string `json:"action_reason"`
+	Change          Change `json:"change"`
+}
+
+// Change is the before and after values of a resource or output. The values
+// are the decoded JSON, the unknown and sensitive values are true, or a nested
+// value of the same shape with the unknown and sensitive attributes true.
+type Change struct {
+	Actions         []string    `json:"actions"`
+	Before          interface{} `json:"before"`
+	After           interface{} `json:"after"`
+	AfterUnknown    interface{} `json:"after_unknown"`
+	BeforeSensitive interface{} `json:"before_sensitive"`
+	AfterSensitive  interface{} `json:"after_sensitive"`
+}
+
+// Action returns the action of the change, like in the terraform logs, where
+// a delete and create, in any order, is a replace.
+func (c Change) Action() string {
+	switch len(c.Actions) {
+	case 0:
+		return ""
+	case 1:
+		return c.Actions[0]
+	}
+	return "replace"
+}
+
+// ReadWorkspacePlanJSONOutput returns the JSON execution plan, available
+// once the plan finishes.
+func (c *TFEClientImpl) ReadWorkspacePlanJSONOutput(ctx context.Context, planID string) (*PlanJSON, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	content, err := c.client.Plans.ReadJSONOutput(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := PlanJSON{}
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return nil, fmt.Errorf("error decoding the plan JSON output: %w", err)
+	}
+	return &plan, nil
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// workspaceProjects are the projects of the workspaces, for the project:
+// filters the API does not support.
+type workspaceProjects struct {
+	// ids are the project IDs by workspace ID.
+	ids map[string]string
+	// names are the project names by project ID.
+	names map[string]string
+}
+
+type projectsDocument struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name string `json:"name"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		Pagination *tfe.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+// listProjectNames returns the names of the organization projects by ID, all
+// pages.
+func (c *TFEClientImpl) listProjectNames(ctx context.Context, org string) (map[string]string, error) {
+	names := map[string]string{}
+	for page := 1; ; page++ {
+		doc, err := c.listProjectsPage(ctx, org, page)
+		if err != nil {
+			return nil, fmt.Errorf("error listing the projects: %w", err)
+		}
+
+		for _, d := range doc.Data {
+			names[d.ID] = d.Attributes.Name
+		}
+		if doc.Meta.Pagination == nil || doc.Meta.Pagination.NextPage == 0 {
+			return names, nil
+		}
+	}
+}
+
+func (c *TFEClientImpl) listProjectsPage(ctx context.Context, org string, pageNumber int) (*projectsDocument, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("page[number]", strconv.Itoa(pageNumber))
+	query.Set("page[size]", "100")
+
+	doc := projectsDocument{}
+	path := fmt.Sprintf("organizations/%s/projects?%s", url.PathEscape(org), query.Encode())
+	if err := c.getAPI(ctx, path, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// projectID returns the ID of the project, by name or ID.
+func projectID(names map[string]string, project string) (string, bool) {
+	for id, name := range names {
+		if project == id || strings.EqualFold(project, name) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// matchProject matches the workspaces in any of the projects, by name or ID.
+func matchProject(projects *workspaceProjects, values []string) func(w *tfe.Workspace) bool {
+	return func(w *tfe.Workspace) bool {
+		id, ok := projects.ids[w.ID]
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if v == id || strings.EqualFold(v, projects.names[id]) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectWorkspaces are the workspaces listed by the test server, with their
+// project and tags.
+var projectWorkspaces = []struct {
+	id, name, project, tags string
+}{
+	{id: "ws-prod", name: "app-prod", project: "prj-platform", tags: "app,prod"},
+	{id: "ws-dev", name: "app-dev", project: "prj-default", tags: "app,legacy"},
+}
+
+// projectWorkspacesPage returns the workspaces of the project and without the
+// excluded tag, if any.
+func projectWorkspacesPage(project, excludeTag string) string {
+	data := []string{}
+	for _, w := range projectWorkspaces {
+		if project != "" && w.project != project {
+			continue
+		}
+		if excludeTag != "" && strings.Contains(w.tags, excludeTag) {
+			continue
+		}
+		data = append(data, fmt.Sprintf(`{
+      "id": %q,
+      "type": "workspaces",
+      "attributes": {"name": %q},
+      "relationships": {"project": {"data": {"id": %q, "type": "projects"}}}
+    }`, w.id, w.name, w.project))
+	}
+	return fmt.Sprintf(`{
+  "data": [%s],
+  "meta": {"pagination": {"current-page": 1, "total-pages": 1, "total-count": %d}}
+}`, strings.Join(data, ","), len(data))
+}
+
+const projectsPage = `{
+  "data": [
+    {"id": "prj-default", "type": "projects", "attributes": {"name": "Default Project"}},
+    {"id": "prj-platform", "type": "projects", "attributes": {"name": "platform"}}
+  ],
+  "meta": {"pagination": {"current-page": 1, "total-pages": 1, "total-count": 2}}
+}`
+
+// newProjectsServer starts a server listing the projectWorkspaces, and
+// returns the queries of the workspaces listings.
+func newProjectsServer(t *testing.T) *[]url.Values {
+	t.Helper()
+
+	queries := []url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/organizations/acme/projects":
+			fmt.Fprint(w, projectsPage)
+		case "/api/v2/organizations/acme/workspaces":
+			query := r.URL.Query()
+			queries = append(queries, query)
+			fmt.Fprint(w, projectWorkspacesPage(query.Get("filter[project][id]"), query.Get("search[exclude-tags]")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL, "TFE_TOKEN": "test-token"}, "")
+	return &queries
+}
+
+func TestListWorkspacesByProject(t *testing.T) {
+	queries := newProjectsServer(t)
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		query           string
+		expected        []string
+		expectedProject string
+	}{
+		{query: "project:platform", expected: []string{"app-prod"}, expectedProject: "prj-platform"},
+		{query: "project:prj-default", expected: []string{"app-dev"}, expectedProject: "prj-default"},
+		{query: "project:unknown", expected: []string{}},
+		{query: `project:"default project",platform`, expected: []string{"app-prod", "app-dev"}},
+		{query: "-project:platform", expected: []string{"app-dev"}},
+		{query: "-tag:legacy", expected: []string{"app-prod"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			*queries = nil
+			workspaces, err := c.ListWorkspaces(context.Background(), "acme", tc.query, -1)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, w := range workspaces.Items {
+				names = append(names, w.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+
+			// a single request per page, with the filters the API supports
+			if tc.query == "project:unknown" {
+				assert.Empty(t, *queries)
+				return
+			}
+			require.Len(t, *queries, 1)
+			assert.Equal(t, tc.expectedProject, (*queries)[0].Get("filter[project][id]"))
+		})
+	}
+}
+
+func TestListWorkspacesCanceled(t *testing.T) {
+	newProjectsServer(t)
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.ListWorkspaces(ctx, "acme", "status:errored", -1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
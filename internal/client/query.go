@@ -0,0 +1,355 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// QueryError is the error of a workspace search query that can't be parsed.
+type QueryError struct {
+	msg string
+}
+
+func (e *QueryError) Error() string {
+	return e.msg
+}
+
+func queryErrorf(format string, a ...interface{}) error {
+	return &QueryError{msg: fmt.Sprintf(format, a...)}
+}
+
+// WorkspaceQuery is a parsed workspace search, e.g.
+//
+//	app "eu west" tags:prod,app -tag:legacy status:errored tf:1.3.* locked:true mode:agent project:platform
+//
+// The text, the tags, the excluded tags and a single project are searched by
+// the API, the other filters are applied to the workspaces listed. Prefixing
+// a filter with "-" excludes the workspaces matching it and quoting a word
+// searches it as text.
+type WorkspaceQuery struct {
+	// Text is searched in the workspace names.
+	Text string
+	// Tags must all be set on the workspaces.
+	Tags []string
+	// ExcludeTags must not be set on the workspaces.
+	ExcludeTags []string
+	// Project is the project of the workspaces, by name or ID, when the query
+	// has a single project: filter with a single project.
+	Project string
+
+	filters []workspaceFilter
+	// projects are read by the client when the query has project: filters the
+	// API does not support, several projects or excluded ones.
+	projects      *workspaceProjects
+	needsProjects bool
+}
+
+// workspaceFilter is a filter the API does not support.
+type workspaceFilter struct {
+	negate bool
+	match  func(w *tfe.Workspace) bool
+}
+
+// queryFilters are the filters of the queries, with their aliases.
+var queryFilters = map[string]string{
+	"tags":    "tags",
+	"tag":     "tags",
+	"t":       "tags",
+	"status":  "status",
+	"tf":      "tf",
+	"locked":  "locked",
+	"mode":    "mode",
+	"project": "project",
+}
+
+var runStatuses = []tfe.RunStatus{
+	tfe.RunApplied,
+	tfe.RunApplyQueued,
+	tfe.RunApplying,
+	tfe.RunCanceled,
+	tfe.RunConfirmed,
+	tfe.RunCostEstimated,
+	tfe.RunCostEstimating,
+	tfe.RunDiscarded,
+	tfe.RunErrored,
+	tfe.RunFetching,
+	tfe.RunPending,
+	tfe.RunPlanQueued,
+	tfe.RunPlanned,
+	tfe.RunPlannedAndFinished,
+	tfe.RunPlanning,
+	tfe.RunPolicyChecked,
+	tfe.RunPolicyChecking,
+	tfe.RunPolicyOverride,
+	tfe.RunPolicySoftFailed,
+	tfe.RunPostPlanRunning,
+	tfe.RunPostPlanCompleted,
+}
+
+var executionModes = []string{"remote", "local", "agent"}
+
+// ParseWorkspaceQuery parses a workspace search. The errors are QueryErrors
+// explaining what is wrong with the query.
+func ParseWorkspaceQuery(searchText string) (*WorkspaceQuery, error) {
+	tokens, err := splitQuery(searchText)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &WorkspaceQuery{projects: &workspaceProjects{}}
+	text := []string{}
+	projectFilters := []workspaceFilter{}
+	projectValues := []string{}
+	for _, t := range tokens {
+		if t.text == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(t.text, ":")
+		if t.quoted || !found {
+			text = append(text, t.text)
+			continue
+		}
+
+		negate := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+		name, ok := queryFilters[key]
+		if !ok {
+			return nil, queryErrorf("unknown filter %s:, use tags:, status:, tf:, locked:, mode: or project:", key)
+		}
+
+		values := splitValues(value)
+		if len(values) == 0 {
+			return nil, queryErrorf("the filter %s: needs a value, e.g. %s", key, filterExample(name))
+		}
+
+		var match func(w *tfe.Workspace) bool
+		switch name {
+		case "tags":
+			if negate {
+				q.ExcludeTags = append(q.ExcludeTags, values...)
+			} else {
+				q.Tags = append(q.Tags, values...)
+			}
+			continue
+		case "status":
+			match, err = matchStatus(values)
+		case "tf":
+			match, err = matchTerraformVersion(values)
+		case "locked":
+			match, err = matchLocked(values)
+		case "mode":
+			match, err = matchExecutionMode(values)
+		case "project":
+			projectFilters = append(projectFilters, workspaceFilter{negate: negate, match: matchProject(q.projects, values)})
+			projectValues = values
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		q.filters = append(q.filters, workspaceFilter{negate: negate, match: match})
+	}
+	q.Text = strings.Join(text, " ")
+
+	// the API filters the workspaces of a single project only
+	if len(projectFilters) == 1 && !projectFilters[0].negate && len(projectValues) == 1 {
+		q.Project = projectValues[0]
+	} else if len(projectFilters) > 0 {
+		q.filters = append(q.filters, projectFilters...)
+		q.needsProjects = true
+	}
+
+	return q, nil
+}
+
+// HasFilters returns true if the query has filters the API does not support.
+func (q *WorkspaceQuery) HasFilters() bool {
+	return len(q.filters) > 0
+}
+
+// Matches returns true if the workspace matches the text, the tags and the
+// filters of the query. The project is not matched, the workspaces do not
+// hold it.
+func (q *WorkspaceQuery) Matches(w *tfe.Workspace) bool {
+	if !strings.Contains(w.Name, q.Text) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !contains(w.TagNames, tag) {
+			return false
+		}
+	}
+	for _, tag := range q.ExcludeTags {
+		if contains(w.TagNames, tag) {
+			return false
+		}
+	}
+	return q.MatchesFilters(w)
+}
+
+// MatchesFilters returns true if the workspace matches the filters the API
+// does not support.
+func (q *WorkspaceQuery) MatchesFilters(w *tfe.Workspace) bool {
+	for _, f := range q.filters {
+		if f.match(w) == f.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func matchStatus(values []string) (func(w *tfe.Workspace) bool, error) {
+	for _, v := range values {
+		if !isRunStatus(v) {
+			return nil, queryErrorf("unknown run status %s, e.g. applied, planned or errored", v)
+		}
+	}
+
+	return func(w *tfe.Workspace) bool {
+		if w.CurrentRun == nil {
+			return false
+		}
+		for _, v := range values {
+			if string(w.CurrentRun.Status) == v {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func isRunStatus(s string) bool {
+	for _, status := range runStatuses {
+		if string(status) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTerraformVersion(patterns []string) (func(w *tfe.Workspace) bool, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, queryErrorf("invalid terraform version %s, e.g. 1.3.* or 1.?.0", p)
+		}
+	}
+
+	return func(w *tfe.Workspace) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, w.TerraformVersion); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func matchLocked(values []string) (func(w *tfe.Workspace) bool, error) {
+	if len(values) != 1 {
+		return nil, queryErrorf("the filter locked: must be true or false")
+	}
+	locked, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return nil, queryErrorf("the filter locked: must be true or false")
+	}
+
+	return func(w *tfe.Workspace) bool {
+		return w.Locked == locked
+	}, nil
+}
+
+func matchExecutionMode(values []string) (func(w *tfe.Workspace) bool, error) {
+	for _, v := range values {
+		if !contains(executionModes, v) {
+			return nil, queryErrorf("unknown execution mode %s, use remote, local or agent", v)
+		}
+	}
+
+	return func(w *tfe.Workspace) bool {
+		return contains(values, w.ExecutionMode)
+	}, nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func filterExample(name string) string {
+	switch name {
+	case "status":
+		return "status:errored"
+	case "tf":
+		return "tf:1.3.*"
+	case "locked":
+		return "locked:true"
+	case "mode":
+		return "mode:agent"
+	case "project":
+		return "project:platform"
+	}
+	return "tags:prod,app"
+}
+
+// splitValues splits the comma separated values of a filter.
+func splitValues(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+type queryToken struct {
+	text string
+	// quoted is set for the tokens starting with a quote, searched as text.
+	quoted bool
+}
+
+// splitQuery splits the query in the words separated by spaces, keeping the
+// spaces between double quotes.
+func splitQuery(searchText string) ([]queryToken, error) {
+	tokens := []queryToken{}
+	b := strings.Builder{}
+	inToken, inQuotes, quoted := false, false, false
+
+	for _, r := range searchText {
+		switch {
+		case r == '"':
+			if !inToken {
+				quoted = true
+			}
+			inToken = true
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if inToken {
+				tokens = append(tokens, queryToken{text: b.String(), quoted: quoted})
+			}
+			b.Reset()
+			inToken, quoted = false, false
+		default:
+			inToken = true
+			b.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, queryErrorf("missing closing quote in %s", searchText)
+	}
+	if inToken {
+		tokens = append(tokens, queryToken{text: b.String(), quoted: quoted})
+	}
+	return tokens, nil
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkspaceQuery(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              string
+		expectedTextSearch string
+		expectedTags       []string
+		expectedExclude    []string
+		expectedProject    string
+		expectedFilters    bool
+	}{
+		{
+			name:               "no tags",
+			input:              "normalsearchstring",
+			expectedTextSearch: "normalsearchstring",
+		},
+		{
+			name:         "only tags",
+			input:        "tags:12345",
+			expectedTags: []string{"12345"},
+		},
+		{
+			name:               "tags and search string",
+			input:              "normalsearchstring tags:12345",
+			expectedTextSearch: "normalsearchstring",
+			expectedTags:       []string{"12345"},
+		},
+		{
+			name:               "two tags and two search string",
+			input:              "normalsearchstring tags:12345 tags:54321 normalsearchstring2",
+			expectedTextSearch: "normalsearchstring normalsearchstring2",
+			expectedTags:       []string{"12345", "54321"},
+		},
+		{
+			name:         "list of tags",
+			input:        "tags:12345,54321",
+			expectedTags: []string{"12345", "54321"},
+		},
+		{
+			name:         "alias t",
+			input:        "t:12345",
+			expectedTags: []string{"12345"},
+		},
+		{
+			name:         "alias tag",
+			input:        "tag:12345",
+			expectedTags: []string{"12345"},
+		},
+		{
+			name:               "quoted phrase",
+			input:              `"app eu" "status:errored"`,
+			expectedTextSearch: "app eu status:errored",
+		},
+		{
+			name:         "quoted value",
+			input:        `tags:"12345, 54321"`,
+			expectedTags: []string{"12345", "54321"},
+		},
+		{
+			name:            "excluded tags",
+			input:           "-tag:12345 -t:54321",
+			expectedExclude: []string{"12345", "54321"},
+		},
+		{
+			name:            "project",
+			input:           "project:platform",
+			expectedProject: "platform",
+		},
+		{
+			name:            "several projects",
+			input:           "project:platform,prj-default",
+			expectedFilters: true,
+		},
+		{
+			name:            "excluded project",
+			input:           "-project:platform",
+			expectedFilters: true,
+		},
+		{
+			name:               "filters",
+			input:              "app status:errored,applied tf:1.3.* locked:true mode:agent",
+			expectedTextSearch: "app",
+			expectedFilters:    true,
+		},
+		{
+			name:  "all empty",
+			input: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseWorkspaceQuery(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTextSearch, q.Text)
+			assert.Equal(t, tc.expectedTags, q.Tags)
+			assert.Equal(t, tc.expectedExclude, q.ExcludeTags)
+			assert.Equal(t, tc.expectedProject, q.Project)
+			assert.Equal(t, tc.expectedFilters, q.HasFilters())
+		})
+	}
+}
+
+func TestParseWorkspaceQueryErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{input: "owner:me", expectedError: "unknown filter owner:, use tags:, status:, tf:, locked:, mode: or project:"},
+		{input: "status:", expectedError: "the filter status: needs a value, e.g. status:errored"},
+		{input: "status:broken", expectedError: "unknown run status broken, e.g. applied, planned or errored"},
+		{input: "tf:1.[", expectedError: "invalid terraform version 1.[, e.g. 1.3.* or 1.?.0"},
+		{input: "locked:maybe", expectedError: "the filter locked: must be true or false"},
+		{input: "mode:cloud", expectedError: "unknown execution mode cloud, use remote, local or agent"},
+		{input: "project:", expectedError: "the filter project: needs a value, e.g. project:platform"},
+		{input: `"app eu`, expectedError: `missing closing quote in "app eu`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := ParseWorkspaceQuery(tc.input)
+			var queryErr *QueryError
+			require.ErrorAs(t, err, &queryErr)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestWorkspaceQueryMatches(t *testing.T) {
+	prod := &tfe.Workspace{
+		Name:             "app-prod",
+		TagNames:         []string{"app", "prod"},
+		TerraformVersion: "1.3.7",
+		ExecutionMode:    "agent",
+		Locked:           true,
+		CurrentRun:       &tfe.Run{Status: tfe.RunErrored},
+	}
+	dev := &tfe.Workspace{
+		Name:             "app-dev",
+		TagNames:         []string{"app", "dev", "legacy"},
+		TerraformVersion: "1.1.9",
+		ExecutionMode:    "remote",
+	}
+
+	tests := []struct {
+		input    string
+		expected []*tfe.Workspace
+	}{
+		{input: "app", expected: []*tfe.Workspace{prod, dev}},
+		{input: "tags:app,prod", expected: []*tfe.Workspace{prod}},
+		{input: "tags:app -tag:legacy", expected: []*tfe.Workspace{prod}},
+		{input: "status:errored", expected: []*tfe.Workspace{prod}},
+		{input: "-status:errored", expected: []*tfe.Workspace{dev}},
+		{input: "tf:1.3.*", expected: []*tfe.Workspace{prod}},
+		{input: "tf:1.1.*,1.3.*", expected: []*tfe.Workspace{prod, dev}},
+		{input: "locked:false", expected: []*tfe.Workspace{dev}},
+		{input: "mode:agent", expected: []*tfe.Workspace{prod}},
+		{input: "app-dev mode:agent", expected: []*tfe.Workspace{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseWorkspaceQuery(tc.input)
+			require.NoError(t, err)
+
+			found := []*tfe.Workspace{}
+			for _, w := range []*tfe.Workspace{prod, dev} {
+				if q.Matches(w) {
+					found = append(found, w)
+				}
+			}
+			assert.Equal(t, tc.expected, found)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// RecorderMode defines if the Recorder talks to the real API or replays
+// previously recorded exchanges.
+type RecorderMode int
+
+const (
+	// ModeReplay serves the responses from the cassette file.
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards requests to the API and saves the exchanges.
+	ModeRecord
+)
+
+var (
+	emailRX  = regexp.MustCompile(`[\w.+\-]+@[\w\-]+(\.[\w\-]+)+`)
+	tokenRX  = regexp.MustCompile(`("[\w\-]*token[\w\-]*"\s*:\s*)"[^"]*"`)
+	objectRX = regexp.MustCompile(`/v1/object/[^"?\s]+`)
+)
+
+// Cassette holds the recorded HTTP exchanges.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded HTTP request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type RecordedResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records API exchanges to a cassette
+// file or replays them, so the client can be tested without a Terraform
+// Cloud account. Tokens, emails and signed log URLs are scrubbed before
+// saving.
+type Recorder struct {
+	mode      RecorderMode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[*Interaction]bool
+}
+
+// NewRecorder creates a recorder for the given cassette file. In replay mode
+// the cassette must exist.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: http.DefaultTransport,
+		cassette:  &Cassette{},
+		used:      map[*Interaction]bool{},
+	}
+
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	if err := json.Unmarshal(content, r.cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client using the recorder as transport.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Save writes the recorded exchanges to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.cassette); err != nil {
+		return fmt.Errorf("error saving cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("error saving cassette: %w", err)
+	}
+
+	return os.WriteFile(r.path, buf.Bytes(), 0644)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{}
+	for _, h := range []string{"Content-Type", "TFP-API-Version", "X-RateLimit-Limit"} {
+		if v := resp.Header.Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    sanitize(requestKey(req.URL)),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    sanitize(string(body)),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replay returns the first unused interaction matching the request method,
+// path and query. When none matches the query is ignored, which is needed for
+// the log endpoints that are read in chunks. Once all the matching
+// interactions are used the last one is served again.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := requestKey(req.URL)
+	i := r.find(req.Method, func(recorded string) bool {
+		return recorded == key
+	})
+	if i == nil {
+		i = r.find(req.Method, func(recorded string) bool {
+			u, err := url.Parse(recorded)
+			return err == nil && u.Path == req.URL.Path
+		})
+	}
+	if i == nil {
+		return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, key)
+	}
+	r.used[i] = true
+
+	resp := &http.Response{
+		Status:     fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+		StatusCode: i.Response.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewBufferString(i.Response.Body)),
+		Request:    req,
+	}
+	for k, v := range i.Response.Headers {
+		resp.Header.Set(k, v)
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) find(method string, match func(recorded string) bool) *Interaction {
+	var last *Interaction
+	for _, i := range r.cassette.Interactions {
+		if i.Request.Method != method || !match(i.Request.URL) {
+			continue
+		}
+		if !r.used[i] {
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+// requestKey identifies a request by its path and sorted query, ignoring the
+// host so recordings work for any TFE address.
+func requestKey(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query, err := url.QueryUnescape(u.Query().Encode())
+	if err != nil {
+		query = u.RawQuery
+	}
+	return fmt.Sprintf("%s?%s", u.Path, query)
+}
+
+func sanitize(s string) string {
+	s = emailRX.ReplaceAllString(s, "user@example.com")
+	s = tokenRX.ReplaceAllString(s, `$1"REDACTED"`)
+	// log URLs are signed, keep them distinct but unusable
+	s = objectRX.ReplaceAllStringFunc(s, func(object string) string {
+		return fmt.Sprintf("/v1/object/%x", sha256.Sum256([]byte(object)))[:27]
+	})
+	return s
+}
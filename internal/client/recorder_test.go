@@ -0,0 +1,349 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Set TERRUI_RECORD=1, with credentials for an organization named "acme", to
+// record the cassettes again against the real API.
+const recordEnv = "TERRUI_RECORD"
+
+// newRecordedClient returns a client replaying the given cassette from
+// testdata.
+func newRecordedClient(t *testing.T, cassette string) TFEClient {
+	t.Helper()
+
+	mode := ModeReplay
+	if os.Getenv(recordEnv) != "" {
+		mode = ModeRecord
+	} else {
+		setupCredentialsEnv(t, map[string]string{"TFE_TOKEN": "test-token"}, "")
+	}
+
+	recorder, err := NewRecorder(filepath.Join("testdata", cassette+".json"), mode)
+	require.NoError(t, err)
+	if mode == ModeRecord {
+		t.Cleanup(func() {
+			require.NoError(t, recorder.Save())
+		})
+	}
+
+	c, err := NewTFEClient(Options{HTTPClient: recorder.HTTPClient()})
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestListWorkspacesRecorded(t *testing.T) {
+	c := newRecordedClient(t, "list_workspaces")
+
+	workspaces, err := c.ListWorkspaces(context.Background(), "acme", "app tags:prod", -1)
+	require.NoError(t, err)
+	require.Len(t, workspaces.Items, 2)
+	assert.Equal(t, 1, workspaces.CurrentPage)
+	assert.Equal(t, 2, workspaces.TotalPages)
+	assert.Equal(t, 3, workspaces.TotalCount)
+
+	w := workspaces.Items[1]
+	assert.Equal(t, "app-prod-eu", w.Name)
+	assert.Equal(t, []string{"app", "prod", "eu"}, w.TagNames)
+	assert.Equal(t, "1.0.11", w.TerraformVersion)
+	require.NotNil(t, w.CurrentRun)
+	assert.Equal(t, tfe.RunErrored, w.CurrentRun.Status)
+
+	workspaces, err = c.ListWorkspaces(context.Background(), "acme", "app tags:prod", 2)
+	require.NoError(t, err)
+	require.Len(t, workspaces.Items, 1)
+	assert.Equal(t, 2, workspaces.CurrentPage)
+	assert.Equal(t, "app-prod-us", workspaces.Items[0].Name)
+	assert.Nil(t, workspaces.Items[0].CurrentRun)
+}
+
+func TestSearchWorkspacesRecorded(t *testing.T) {
+	c := newRecordedClient(t, "search_workspaces")
+
+	workspaces, err := c.ListWorkspaces(context.Background(), "acme", "app tags:prod tf:1.1.*", -1)
+	require.NoError(t, err)
+	require.Len(t, workspaces.Items, 2)
+	assert.Equal(t, 1, workspaces.CurrentPage)
+	assert.Equal(t, 1, workspaces.TotalPages)
+	assert.Equal(t, 2, workspaces.TotalCount)
+	assert.Equal(t, "app-prod", workspaces.Items[0].Name)
+	assert.Equal(t, "app-prod-us", workspaces.Items[1].Name)
+}
+
+func TestReadWorkspaceRecorded(t *testing.T) {
+	c := newRecordedClient(t, "read_workspace")
+
+	w, err := c.ReadWorkspace(context.Background(), "acme", "app-prod")
+	require.NoError(t, err)
+	assert.Equal(t, "ws-2Bv8Yy1wJ4fRvTnJ", w.ID)
+	assert.True(t, w.Locked)
+	assert.Equal(t, 25, w.RunsCount)
+
+	require.NotNil(t, w.CurrentRun)
+	assert.Equal(t, tfe.RunApplied, w.CurrentRun.Status)
+	assert.Equal(t, "jdoe", w.CurrentRun.CreatedBy.Username)
+	assert.Equal(t, "user@example.com", w.CurrentRun.CreatedBy.Email)
+	assert.Equal(t, 2, w.CurrentRun.Plan.ResourceAdditions)
+	assert.Equal(t, "apply-47MBvjwzBG8YKc2v", w.CurrentRun.Apply.ID)
+}
+
+func TestListWorkspaceTeamAccessesRecorded(t *testing.T) {
+	c := newRecordedClient(t, "team_accesses")
+
+	accesses, err := c.ListWorkspaceTeamAccesses(context.Background(), "ws-2Bv8Yy1wJ4fRvTnJ")
+	require.NoError(t, err)
+	require.Len(t, accesses.Items, 2)
+
+	assert.Equal(t, tfe.AccessAdmin, accesses.Items[0].Access)
+	assert.Equal(t, "owners", accesses.Items[0].Team.Name)
+	assert.Equal(t, tfe.AccessRead, accesses.Items[1].Access)
+	assert.Equal(t, "developers", accesses.Items[1].Team.Name)
+	assert.Equal(t, 12, accesses.Items[1].Team.UserCount)
+}
+
+func TestReadWorkspaceLogsRecorded(t *testing.T) {
+	c := newRecordedClient(t, "logs")
+
+	planLogs, err := c.ReadWorkspacePlanLogs(context.Background(), "plan-AbtVzkzF5hn1Y1Ce")
+	require.NoError(t, err)
+	content, err := io.ReadAll(planLogs)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Terraform v1.1.9\n")
+	assert.Contains(t, string(content), "Plan: 1 to add, 0 to change, 0 to destroy.")
+	assert.NotContains(t, string(content), "\x02")
+
+	applyLogs, err := c.ReadWorkspaceApplyLogs(context.Background(), "apply-47MBvjwzBG8YKc2v")
+	require.NoError(t, err)
+	content, err = io.ReadAll(applyLogs)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.")
+	assert.NotContains(t, string(content), "\x03")
+}
+
+func TestRecorderRecordSanitizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": {"attributes": {"email": "jane@acme.com", "token": "s3cr3t", "log-read-url": "https://archivist.terraform.io/v1/object/dmF1bHQ6djE6c2lnbmVk"}}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := NewRecorder(path, ModeRecord)
+	require.NoError(t, err)
+
+	resp, err := recorder.HTTPClient().Get(server.URL + "/api/v2/account/details?b=2&a=1")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "s3cr3t", "the caller gets the real response")
+	require.NoError(t, recorder.Save())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "/api/v2/account/details?a=1&b=2")
+	assert.Contains(t, string(content), "user@example.com")
+	assert.NotContains(t, string(content), "jane@acme.com")
+	assert.NotContains(t, string(content), "s3cr3t")
+	assert.NotContains(t, string(content), "dmF1bHQ6djE6c2lnbmVk")
+
+	replay, err := NewRecorder(path, ModeReplay)
+	require.NoError(t, err)
+	resp, err = replay.HTTPClient().Get("https://app.terraform.io/api/v2/account/details?a=1&b=2")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestReadWorkspacePlanJSONOutputRecorded(t *testing.T) {
+	c := newRecordedClient(t, "plan_json_output")
+
+	plan, err := c.ReadWorkspacePlanJSONOutput(context.Background(), "plan-AbtVzkzF5hn1Y1Ce")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.9", plan.TerraformVersion)
+	require.Len(t, plan.ResourceChanges, 2)
+
+	bucket := plan.ResourceChanges[0]
+	assert.Equal(t, "aws_s3_bucket.logs", bucket.Address)
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", bucket.ProviderName)
+	assert.Equal(t, "create", bucket.Change.Action())
+	assert.Nil(t, bucket.Change.Before)
+	assert.Equal(t, map[string]interface{}{"arn": true, "id": true, "tags": map[string]interface{}{}}, bucket.Change.AfterUnknown)
+
+	db := plan.ResourceChanges[1]
+	assert.Equal(t, "module.db", db.ModuleAddress)
+	assert.Equal(t, "replace", db.Change.Action())
+	assert.Equal(t, map[string]interface{}{"password": true}, db.Change.AfterSensitive)
+}
+
+func TestStateVersionsRecorded(t *testing.T) {
+	c := newRecordedClient(t, "state_versions")
+
+	versions, err := c.ListWorkspaceStateVersions(context.Background(), "acme", "app-prod", -1)
+	require.NoError(t, err)
+	require.Len(t, versions.Items, 2)
+	assert.Equal(t, 2, versions.TotalCount)
+
+	sv := versions.Items[0]
+	assert.Equal(t, int64(3), sv.Serial)
+	assert.Equal(t, "run-CZcmD7eagjhyX0vN", sv.RunID)
+	assert.Equal(t, 3, sv.ResourceCount())
+	assert.False(t, versions.Items[1].ResourcesProcessed)
+	assert.Equal(t, "", versions.Items[1].RunID)
+
+	state, err := c.DownloadStateVersion(context.Background(), sv.DownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), state.Serial)
+	require.Len(t, state.Resources, 2)
+
+	web := state.Resources[1]
+	assert.Equal(t, "module.app.aws_instance.web", web.Address())
+	assert.Equal(t, "module.app.aws_instance.web[1]", web.InstanceAddress(web.Instances[1]))
+	key, ok := web.Instances[0].SensitiveAttributes[0][0].Key()
+	assert.True(t, ok)
+	assert.Equal(t, "user_data", key)
+}
+
+func TestListWorkspaceOutputsRecorded(t *testing.T) {
+	c := newRecordedClient(t, "outputs")
+
+	outputs, err := c.ListWorkspaceOutputs(context.Background(), "ws-prod")
+	require.NoError(t, err)
+	require.Len(t, outputs, 3)
+
+	assert.Equal(t, "bucket", outputs[0].Name)
+	assert.Equal(t, "acme-logs", outputs[0].Value)
+	assert.Equal(t, map[string]interface{}{
+		"api":   "https://api.acme.example.com",
+		"ports": []interface{}{float64(80), float64(443)},
+	}, outputs[1].Value)
+
+	// the sensitive values are read one by one
+	assert.True(t, outputs[2].Sensitive)
+	assert.Nil(t, outputs[2].Value)
+	output, err := c.ReadWorkspaceOutput(context.Background(), outputs[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", output.Value)
+
+	outputs, err = c.ListWorkspaceOutputs(context.Background(), "ws-empty")
+	require.NoError(t, err)
+	assert.Empty(t, outputs)
+}
+
+func TestRunActionsRecorded(t *testing.T) {
+	c := newRecordedClient(t, "run_actions")
+	ctx := context.Background()
+
+	run, err := c.CreateRun(ctx, "ws-prod", RunOptions{
+		Message: "Queued from terrui",
+		Targets: []string{"aws_s3_bucket.logs"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "run-9rVfpXf1vAVXjRuq", run.ID)
+	assert.Equal(t, tfe.RunPending, run.Status)
+	assert.Equal(t, []string{"aws_s3_bucket.logs"}, run.TargetAddrs)
+	assert.True(t, run.Actions.IsCancelable)
+
+	assert.NoError(t, c.ApplyRun(ctx, run.ID))
+	assert.NoError(t, c.DiscardRun(ctx, run.ID))
+	assert.NoError(t, c.CancelRun(ctx, run.ID))
+	assert.NoError(t, c.ForceCancelRun(ctx, run.ID))
+
+	err = c.ApplyRun(ctx, "run-CZcmD7eagjhyX0vN")
+	assert.ErrorContains(t, err, "transition not allowed")
+}
+
+func TestWorkspaceLockRecorded(t *testing.T) {
+	c := newRecordedClient(t, "workspace_lock")
+	ctx := context.Background()
+
+	lock, err := c.ReadWorkspaceLock(ctx, "ws-2Bv8Yy1wJ4fRvTnJ")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, "user-V3R563qtJNcExAkN", lock.HolderID)
+	assert.Equal(t, "jdoe (user)", lock.Holder())
+
+	lock, err = c.ReadWorkspaceLock(ctx, "ws-kPmZ4bR3tQcVx2Lw")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, "run-CZcmD7eagjhyX0vN (run)", lock.Holder())
+
+	lock, err = c.ReadWorkspaceLock(ctx, "ws-u7LwRfCq1vYk9XnE")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+
+	assert.NoError(t, c.UnlockWorkspace(ctx, "ws-2Bv8Yy1wJ4fRvTnJ"))
+	assert.NoError(t, c.LockWorkspace(ctx, "ws-2Bv8Yy1wJ4fRvTnJ", "incident 42"))
+	assert.NoError(t, c.ForceUnlockWorkspace(ctx, "ws-2Bv8Yy1wJ4fRvTnJ"))
+
+	err = c.UnlockWorkspace(ctx, "ws-kPmZ4bR3tQcVx2Lw")
+	assert.ErrorIs(t, err, tfe.ErrWorkspaceLockedByRun)
+}
+
+func TestVariablesRecorded(t *testing.T) {
+	c := newRecordedClient(t, "variables")
+	ctx := context.Background()
+
+	variables, err := c.ListWorkspaceVariables(ctx, "ws-prod", 2)
+	require.NoError(t, err)
+	require.Len(t, variables.Items, 3)
+	assert.Equal(t, 2, variables.CurrentPage)
+	assert.Equal(t, 33, variables.TotalCount)
+	assert.True(t, variables.Items[1].Sensitive)
+	assert.Empty(t, variables.Items[1].Value)
+	assert.Equal(t, tfe.CategoryEnv, variables.Items[2].Category)
+
+	v, err := c.CreateVariable(ctx, "ws-prod", VariableOptions{
+		Key:         "allowed_cidrs",
+		Value:       tfe.String(`["10.0.0.0/8"]`),
+		Description: "CIDRs allowed to connect",
+		Category:    tfe.CategoryTerraform,
+		HCL:         true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "var-kZjwQ8cGd1Uh3Jps", v.ID)
+	assert.True(t, v.HCL)
+
+	v, err = c.UpdateVariable(ctx, "ws-prod", v.ID, VariableOptions{
+		Key:         "allowed_cidrs",
+		Description: "CIDRs allowed to connect",
+		HCL:         true,
+		Sensitive:   true,
+	})
+	require.NoError(t, err)
+	assert.True(t, v.Sensitive)
+
+	assert.NoError(t, c.DeleteVariable(ctx, "ws-prod", v.ID))
+}
+
+func TestVariableSetsRecorded(t *testing.T) {
+	c := newRecordedClient(t, "variable_sets")
+
+	sets, err := c.ListWorkspaceVariableSets(context.Background(), "ws-prod")
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+
+	assert.Equal(t, "aws-credentials", sets[0].Name)
+	assert.True(t, sets[0].Global)
+	require.Len(t, sets[0].Variables, 2)
+	assert.Equal(t, tfe.CategoryEnv, sets[0].Variables[0].Category)
+	assert.True(t, sets[0].Variables[1].Sensitive)
+	assert.Empty(t, sets[0].Variables[1].Value)
+
+	assert.Equal(t, "prod-defaults", sets[1].Name)
+	require.Len(t, sets[1].Workspaces, 2)
+	assert.Equal(t, "ws-prod", sets[1].Workspaces[0].ID)
+	require.Len(t, sets[1].Variables, 2)
+	assert.Equal(t, "region", sets[1].Variables[0].Key)
+	assert.Equal(t, "us-east-1", sets[1].Variables[0].Value)
+}
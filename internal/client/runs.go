@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/jsonapi"
+)
+
+// RunOptions are the options of a new run, a plan and apply run by default.
+type RunOptions struct {
+	Message     string
+	PlanOnly    bool
+	IsDestroy   bool
+	RefreshOnly bool
+	Targets     []string
+	Replaces    []string
+}
+
+// runCreateOptions is the run created with the API, as go-tfe does not
+// support the plan only runs.
+type runCreateOptions struct {
+	Type         string         `jsonapi:"primary,runs"`
+	Message      string         `jsonapi:"attr,message,omitempty"`
+	PlanOnly     bool           `jsonapi:"attr,plan-only,omitempty"`
+	IsDestroy    bool           `jsonapi:"attr,is-destroy,omitempty"`
+	RefreshOnly  bool           `jsonapi:"attr,refresh-only,omitempty"`
+	TargetAddrs  []string       `jsonapi:"attr,target-addrs,omitempty"`
+	ReplaceAddrs []string       `jsonapi:"attr,replace-addrs,omitempty"`
+	Workspace    *tfe.Workspace `jsonapi:"relation,workspace"`
+}
+
+// CreateRun queues a run in the workspace.
+func (c *TFEClientImpl) CreateRun(ctx context.Context, workspaceID string, options RunOptions) (*tfe.Run, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	content, err := c.doAPI(ctx, http.MethodPost, "runs", &runCreateOptions{
+		Message:      options.Message,
+		PlanOnly:     options.PlanOnly,
+		IsDestroy:    options.IsDestroy,
+		RefreshOnly:  options.RefreshOnly,
+		TargetAddrs:  options.Targets,
+		ReplaceAddrs: options.Replaces,
+		Workspace:    &tfe.Workspace{ID: workspaceID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	run := &tfe.Run{}
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(content), run); err != nil {
+		return nil, fmt.Errorf("error decoding the run: %w", err)
+	}
+	return run, nil
+}
+
+func (c *TFEClientImpl) ApplyRun(ctx context.Context, runID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return singleLineError(c.client.Runs.Apply(ctx, runID, tfe.RunApplyOptions{}))
+}
+
+func (c *TFEClientImpl) DiscardRun(ctx context.Context, runID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return singleLineError(c.client.Runs.Discard(ctx, runID, tfe.RunDiscardOptions{}))
+}
+
+func (c *TFEClientImpl) CancelRun(ctx context.Context, runID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return singleLineError(c.client.Runs.Cancel(ctx, runID, tfe.RunCancelOptions{}))
+}
+
+// ForceCancelRun ends a run that did not stop after being canceled, the API
+// only allows it a while after the cancel.
+func (c *TFEClientImpl) ForceCancelRun(ctx context.Context, runID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return singleLineError(c.client.Runs.ForceCancel(ctx, runID, tfe.RunForceCancelOptions{}))
+}
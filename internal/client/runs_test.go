@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRunRequest(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/runs":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/vnd.api+json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"data": {"id": "run-1", "type": "runs", "attributes": {"status": "pending"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL, "TFE_TOKEN": "test-token"}, "")
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	run, err := c.CreateRun(context.Background(), "ws-prod", RunOptions{
+		Message:  "check drift",
+		PlanOnly: true,
+		Replaces: []string{"aws_instance.web[0]"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", run.ID)
+
+	assert.Equal(t, map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "runs",
+			"attributes": map[string]interface{}{
+				"message":       "check drift",
+				"plan-only":     true,
+				"replace-addrs": []interface{}{"aws_instance.web[0]"},
+			},
+			"relationships": map[string]interface{}{
+				"workspace": map[string]interface{}{
+					"data": map[string]interface{}{"type": "workspaces", "id": "ws-prod"},
+				},
+			},
+		},
+	}, body)
+}
+
+func TestRunActionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/runs":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"errors": [{"status": "422", "title": "invalid attribute", "detail": "Workspace is locked"}]}`)
+		case "/api/v2/runs/run-1/actions/apply":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"errors": [{"status": "409", "title": "transition not allowed", "detail": "Run is not in a confirmable state"}]}`)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL, "TFE_TOKEN": "test-token"}, "")
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	_, err = c.CreateRun(context.Background(), "ws-prod", RunOptions{})
+	assert.EqualError(t, err, "error requesting runs: invalid attribute: Workspace is locked")
+
+	err = c.ApplyRun(context.Background(), "run-1")
+	assert.EqualError(t, err, "transition not allowed: Run is not in a confirmable state")
+
+	_, err = c.ReadWorkspaceLock(context.Background(), "ws-prod")
+	assert.ErrorContains(t, err, "502 Bad Gateway")
+}
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+const stateVersionsPageSize = 20
+
+// StateVersion is a state version of a workspace. go-tfe does not decode the
+// resources of the state versions, so they are read directly from the API.
+type StateVersion struct {
+	ID                 string
+	Serial             int64
+	CreatedAt          time.Time
+	DownloadURL        string
+	RunID              string
+	ResourcesProcessed bool
+	Resources          []StateVersionResource
+}
+
+// StateVersionResource is the count of the instances of a resource in a
+// state version, available once the state version resources are processed.
+type StateVersionResource struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Count    int    `json:"count"`
+	Module   string `json:"module"`
+	Provider string `json:"provider"`
+}
+
+// ResourceCount returns the number of resource instances in the state
+// version.
+func (s *StateVersion) ResourceCount() int {
+	count := 0
+	for _, r := range s.Resources {
+		count += r.Count
+	}
+	return count
+}
+
+type StateVersionList struct {
+	*tfe.Pagination
+	Items []*StateVersion
+}
+
+type stateVersionsDocument struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Serial             int64                  `json:"serial"`
+			CreatedAt          time.Time              `json:"created-at"`
+			DownloadURL        string                 `json:"hosted-state-download-url"`
+			ResourcesProcessed bool                   `json:"resources-processed"`
+			Resources          []StateVersionResource `json:"resources"`
+		} `json:"attributes"`
+		Relationships struct {
+			Run struct {
+				Data *struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			} `json:"run"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Meta struct {
+		Pagination *tfe.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+// State is the terraform state, as stored in the state files. Only the fields
+// used by terrui are decoded.
+type State struct {
+	Version          int                    `json:"version"`
+	TerraformVersion string                 `json:"terraform_version"`
+	Serial           int64                  `json:"serial"`
+	Lineage          string                 `json:"lineage"`
+	Outputs          map[string]StateOutput `json:"outputs"`
+	Resources        []StateResource        `json:"resources"`
+}
+
+type StateOutput struct {
+	Value     interface{} `json:"value"`
+	Type      interface{} `json:"type"`
+	Sensitive bool        `json:"sensitive"`
+}
+
+type StateResource struct {
+	Module    string          `json:"module"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []StateInstance `json:"instances"`
+}
+
+type StateInstance struct {
+	IndexKey            interface{}            `json:"index_key"`
+	SchemaVersion       int                    `json:"schema_version"`
+	Attributes          map[string]interface{} `json:"attributes"`
+	SensitiveAttributes []StatePath            `json:"sensitive_attributes"`
+	Dependencies        []string               `json:"dependencies"`
+}
+
+// StatePath is the path of an attribute, e.g. a sensitive attribute.
+type StatePath []StatePathStep
+
+// StatePathStep is an attribute name, for the get_attr steps, or a map key or
+// list index, for the index steps.
+type StatePathStep struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Key returns the attribute name or the map key of the step.
+func (s StatePathStep) Key() (string, bool) {
+	if s.Type == "get_attr" {
+		key, ok := s.Value.(string)
+		return key, ok
+	}
+
+	key, ok := s.indexValue().(string)
+	return key, ok
+}
+
+// Index returns the list index of the step.
+func (s StatePathStep) Index() (int, bool) {
+	index, ok := s.indexValue().(float64)
+	return int(index), ok
+}
+
+// indexValue returns the value of the index steps, which is typed, e.g.
+// {"value": 0, "type": "number"}.
+func (s StatePathStep) indexValue() interface{} {
+	if s.Type != "index" {
+		return nil
+	}
+	if v, ok := s.Value.(map[string]interface{}); ok {
+		return v["value"]
+	}
+	return nil
+}
+
+// Address returns the address of the resource, without the instance key.
+func (r StateResource) Address() string {
+	address := fmt.Sprintf("%s.%s", r.Type, r.Name)
+	if r.Mode == "data" {
+		address = "data." + address
+	}
+	if r.Module != "" {
+		address = fmt.Sprintf("%s.%s", r.Module, address)
+	}
+	return address
+}
+
+// InstanceAddress returns the address of the resource instance, with its
+// count or for_each key.
+func (r StateResource) InstanceAddress(i StateInstance) string {
+	switch key := i.IndexKey.(type) {
+	case float64:
+		return fmt.Sprintf("%s[%d]", r.Address(), int(key))
+	case string:
+		return fmt.Sprintf("%s[%q]", r.Address(), key)
+	}
+	return r.Address()
+}
+
+// ListWorkspaceStateVersions returns the state versions of the workspace, the
+// most recent first.
+func (c *TFEClientImpl) ListWorkspaceStateVersions(ctx context.Context, org, workspace string, pageNumber int) (*StateVersionList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("filter[organization][name]", org)
+	query.Set("filter[workspace][name]", workspace)
+	query.Set("page[size]", strconv.Itoa(stateVersionsPageSize))
+	if pageNumber != -1 {
+		query.Set("page[number]", strconv.Itoa(pageNumber))
+	}
+
+	doc := stateVersionsDocument{}
+	if err := c.getAPI(ctx, "state-versions?"+query.Encode(), &doc); err != nil {
+		return nil, err
+	}
+
+	list := &StateVersionList{Pagination: doc.Meta.Pagination}
+	if list.Pagination == nil {
+		list.Pagination = &tfe.Pagination{CurrentPage: 1, TotalPages: 1, TotalCount: len(doc.Data)}
+	}
+	for _, d := range doc.Data {
+		sv := &StateVersion{
+			ID:                 d.ID,
+			Serial:             d.Attributes.Serial,
+			CreatedAt:          d.Attributes.CreatedAt,
+			DownloadURL:        d.Attributes.DownloadURL,
+			ResourcesProcessed: d.Attributes.ResourcesProcessed,
+			Resources:          d.Attributes.Resources,
+		}
+		if d.Relationships.Run.Data != nil {
+			sv.RunID = d.Relationships.Run.Data.ID
+		}
+		list.Items = append(list.Items, sv)
+	}
+
+	return list, nil
+}
+
+// ReadCurrentStateVersion returns the current state version of the workspace.
+func (c *TFEClientImpl) ReadCurrentStateVersion(ctx context.Context, workspaceID string) (*StateVersion, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	sv, err := c.client.StateVersions.ReadCurrent(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &StateVersion{
+		ID:          sv.ID,
+		Serial:      sv.Serial,
+		CreatedAt:   sv.CreatedAt,
+		DownloadURL: sv.DownloadURL,
+	}
+	if sv.Run != nil {
+		current.RunID = sv.Run.ID
+	}
+	return current, nil
+}
+
+// DownloadStateVersion downloads and decodes the state of a state version.
+func (c *TFEClientImpl) DownloadStateVersion(ctx context.Context, downloadURL string) (*State, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	content, err := c.client.StateVersions.Download(ctx, downloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	state := State{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("error decoding the state: %w", err)
+	}
+	return &state, nil
+}
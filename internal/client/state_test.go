@@ -0,0 +1,66 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateInstanceAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource StateResource
+		instance StateInstance
+		expected string
+	}{
+		{
+			name:     "single instance",
+			resource: StateResource{Mode: "managed", Type: "aws_s3_bucket", Name: "logs"},
+			expected: "aws_s3_bucket.logs",
+		},
+		{
+			name:     "count",
+			resource: StateResource{Mode: "managed", Type: "aws_instance", Name: "web"},
+			instance: StateInstance{IndexKey: float64(2)},
+			expected: "aws_instance.web[2]",
+		},
+		{
+			name:     "for_each in a module",
+			resource: StateResource{Module: "module.app", Mode: "managed", Type: "aws_instance", Name: "web"},
+			instance: StateInstance{IndexKey: "blue"},
+			expected: `module.app.aws_instance.web["blue"]`,
+		},
+		{
+			name:     "data source",
+			resource: StateResource{Mode: "data", Type: "aws_ami", Name: "ubuntu"},
+			expected: "data.aws_ami.ubuntu",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.resource.InstanceAddress(tc.instance))
+		})
+	}
+}
+
+func TestStatePathStep(t *testing.T) {
+	attr := StatePathStep{Type: "get_attr", Value: "password"}
+	key, ok := attr.Key()
+	assert.True(t, ok)
+	assert.Equal(t, "password", key)
+	_, ok = attr.Index()
+	assert.False(t, ok)
+
+	index := StatePathStep{Type: "index", Value: map[string]interface{}{"value": float64(1), "type": "number"}}
+	i, ok := index.Index()
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+	_, ok = index.Key()
+	assert.False(t, ok)
+
+	mapKey := StatePathStep{Type: "index", Value: map[string]interface{}{"value": "token", "type": "string"}}
+	key, ok = mapKey.Key()
+	assert.True(t, ok)
+	assert.Equal(t, "token", key)
+}
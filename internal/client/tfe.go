@@ -2,36 +2,95 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"io"
-	"os"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/go-tfe"
 )
 
 type TFEClient interface {
-	ListOrganizations(pageNumber int) (*tfe.OrganizationList, error)
-	ListWorkspaces(org string, searchText string, pageNumber int) (*tfe.WorkspaceList, error)
-	ReadWorkspace(org, workspace string) (*tfe.Workspace, error)
-	ListWorkspaceVariables(workspaceID string) (*tfe.VariableList, error)
-	ListWorkspaceRuns(workspaceID string) (*tfe.RunList, error)
-	ListWorkspaceTeamAccesses(workspaceID string) (*tfe.TeamAccessList, error)
-	ReadWorkspaceRun(runID string) (*tfe.Run, error)
-	ReadWorkspacePlan(planID string) (*tfe.Plan, error)
-	ReadWorkspacePlanLogs(planID string) (io.Reader, error)
-	ReadWorkspaceApplyLogs(planID string) (io.Reader, error)
+	ListOrganizations(ctx context.Context, pageNumber int) (*tfe.OrganizationList, error)
+	ListWorkspaces(ctx context.Context, org string, searchText string, pageNumber int) (*tfe.WorkspaceList, error)
+	ReadWorkspace(ctx context.Context, org, workspace string) (*tfe.Workspace, error)
+	ListWorkspaceVariables(ctx context.Context, workspaceID string, pageNumber int) (*tfe.VariableList, error)
+	ListWorkspaceRuns(ctx context.Context, workspaceID string) (*tfe.RunList, error)
+	ListWorkspaceTeamAccesses(ctx context.Context, workspaceID string) (*tfe.TeamAccessList, error)
+	ReadWorkspaceRun(ctx context.Context, runID string) (*tfe.Run, error)
+	ReadWorkspacePlan(ctx context.Context, planID string) (*tfe.Plan, error)
+	ReadWorkspacePlanLogs(ctx context.Context, planID string) (io.Reader, error)
+	ReadWorkspacePlanJSONOutput(ctx context.Context, planID string) (*PlanJSON, error)
+	ReadWorkspaceApplyLogs(ctx context.Context, planID string) (io.Reader, error)
+	ListWorkspaceStateVersions(ctx context.Context, org, workspace string, pageNumber int) (*StateVersionList, error)
+	ReadCurrentStateVersion(ctx context.Context, workspaceID string) (*StateVersion, error)
+	DownloadStateVersion(ctx context.Context, downloadURL string) (*State, error)
+	ListWorkspaceOutputs(ctx context.Context, workspaceID string) ([]*tfe.StateVersionOutput, error)
+	ReadWorkspaceOutput(ctx context.Context, outputID string) (*tfe.StateVersionOutput, error)
+	CreateRun(ctx context.Context, workspaceID string, options RunOptions) (*tfe.Run, error)
+	ApplyRun(ctx context.Context, runID string) error
+	DiscardRun(ctx context.Context, runID string) error
+	CancelRun(ctx context.Context, runID string) error
+	ForceCancelRun(ctx context.Context, runID string) error
+	ReadWorkspaceLock(ctx context.Context, workspaceID string) (*WorkspaceLock, error)
+	LockWorkspace(ctx context.Context, workspaceID, reason string) error
+	UnlockWorkspace(ctx context.Context, workspaceID string) error
+	ForceUnlockWorkspace(ctx context.Context, workspaceID string) error
+	CreateVariable(ctx context.Context, workspaceID string, options VariableOptions) (*tfe.Variable, error)
+	UpdateVariable(ctx context.Context, workspaceID, variableID string, options VariableOptions) (*tfe.Variable, error)
+	DeleteVariable(ctx context.Context, workspaceID, variableID string) error
+	ListVariableSets(ctx context.Context, org string, pageNumber int) (*tfe.VariableSetList, error)
+	ListVariableSetVariables(ctx context.Context, variableSetID string, pageNumber int) (*tfe.VariableSetVariableList, error)
+	ListWorkspaceVariableSets(ctx context.Context, workspaceID string) ([]*tfe.VariableSet, error)
 }
 
 type TFEClientImpl struct {
-	config *tfe.Config
-	client *tfe.Client
+	config  *tfe.Config
+	client  *tfe.Client
+	timeout time.Duration
+	// http sends the API requests go-tfe does not support, sharing the
+	// go-tfe HTTP client and retrying like go-tfe does.
+	http *retryablehttp.Client
 }
 
-func NewTFEClient() (TFEClient, error) {
-	c := TFEClientImpl{}
+// Options configures how the client connects to Terraform Cloud/Enterprise.
+type Options struct {
+	// Hostname of the Terraform Enterprise instance, defaults to app.terraform.io.
+	Hostname string
+	// PreferHostname uses the hostname even if TFE_ADDRESS is set, for the
+	// hostname of a profile chosen explicitly.
+	PreferHostname bool
+	// TokenEnv is the environment variable holding the API token, when empty
+	// the token is discovered like the terraform CLI does.
+	TokenEnv string
+	// Timeout applied to each request, zero means no timeout.
+	Timeout time.Duration
+	// HTTPClient overrides the default HTTP client, e.g. to record requests.
+	HTTPClient *http.Client
+}
+
+func NewTFEClient(options Options) (TFEClient, error) {
+	c := TFEClientImpl{timeout: options.Timeout}
+
+	credentials, err := ResolveCredentials(options.Hostname, options.TokenEnv, options.PreferHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = cleanhttp.DefaultPooledClient()
+	}
 
-	c.config = &tfe.Config{Token: os.Getenv("TFE_TOKEN")}
+	c.config = &tfe.Config{
+		Address:    credentials.Address,
+		Token:      credentials.Token,
+		HTTPClient: httpClient,
+	}
+	c.http = newRetryClient(httpClient)
 
 	client, err := tfe.NewClient(c.config)
 	if err != nil {
@@ -42,7 +101,19 @@ func NewTFEClient() (TFEClient, error) {
 	return &c, nil
 }
 
-func (c *TFEClientImpl) ListOrganizations(pageNumber int) (*tfe.OrganizationList, error) {
+// withTimeout derives a context that expires after the configured request
+// timeout.
+func (c *TFEClientImpl) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func (c *TFEClientImpl) ListOrganizations(ctx context.Context, pageNumber int) (*tfe.OrganizationList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	options := tfe.OrganizationListOptions{
 		ListOptions: tfe.ListOptions{
 			PageSize: 30,
@@ -52,59 +123,123 @@ func (c *TFEClientImpl) ListOrganizations(pageNumber int) (*tfe.OrganizationList
 		options.PageNumber = pageNumber
 	}
 
-	return c.client.Organizations.List(context.Background(), &options)
+	return c.client.Organizations.List(ctx, &options)
 }
 
-func (c *TFEClientImpl) ListWorkspaces(org string, searchText string, pageNumber int) (*tfe.WorkspaceList, error) {
-	options := tfe.ListOptions{PageSize: 30}
-	if pageNumber != -1 {
-		options.PageNumber = pageNumber
+// ListWorkspaces returns the workspaces matching the search, a
+// WorkspaceQuery. The API searches the text, the tags, the excluded tags and
+// a single project, so when the query has other filters all the workspaces
+// found are read and filtered to paginate them.
+func (c *TFEClientImpl) ListWorkspaces(ctx context.Context, org string, searchText string, pageNumber int) (*tfe.WorkspaceList, error) {
+	query, err := ParseWorkspaceQuery(searchText)
+	if err != nil {
+		return nil, err
 	}
 
-	textSearch, tagsSearch := parseSearchText(searchText)
+	params := url.Values{}
+	params.Set("include", string(tfe.WSCurrentRun))
+	if query.Text != "" {
+		params.Set("search[name]", query.Text)
+	}
+	if len(query.Tags) > 0 {
+		params.Set("search[tags]", strings.Join(query.Tags, ","))
+	}
+	if len(query.ExcludeTags) > 0 {
+		params.Set("search[exclude-tags]", strings.Join(query.ExcludeTags, ","))
+	}
 
-	return c.client.Workspaces.List(context.Background(), org, &tfe.WorkspaceListOptions{
-		Include:     []tfe.WSIncludeOpt{"current_run"},
-		Search:      textSearch,
-		Tags:        tagsSearch,
-		ListOptions: options,
-	})
-}
+	if query.Project != "" || query.needsProjects {
+		if query.projects.names, err = c.listProjectNames(ctx, org); err != nil {
+			return nil, err
+		}
+	}
+	if query.Project != "" {
+		id, ok := projectID(query.projects.names, query.Project)
+		if !ok {
+			return paginateWorkspaces(nil, pageNumber, 30), nil
+		}
+		params.Set("filter[project][id]", id)
+	}
 
-func parseSearchText(searchText string) (string, string) {
-	tagsLabels := []string{"tags", "tag", "t"}
-	b := strings.Builder{}
-	var tagsSearch string
-	for _, s := range strings.Split(searchText, " ") {
-		var tags string
-		var found bool
+	if !query.HasFilters() {
+		if pageNumber < 1 {
+			pageNumber = 0
+		}
+		return c.listWorkspacesPage(ctx, org, params, pageNumber, 30, nil)
+	}
 
-		for _, l := range tagsLabels {
-			_, tags, found = strings.Cut(s, fmt.Sprintf("%s:", l))
-			if found {
-				break
-			}
+	// the filters are applied to all the workspaces found, each page with its
+	// own timeout
+	if query.needsProjects {
+		query.projects.ids = map[string]string{}
+	}
+	found := []*tfe.Workspace{}
+	for page := 0; ; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		workspaces, err := c.listWorkspacesPage(ctx, org, params, page, 100, query.projects.ids)
+		if err != nil {
+			return nil, err
 		}
 
-		if found {
-			tagsSearch = tags
-		} else {
-			b.WriteString(s)
-			b.WriteString(" ")
+		for _, w := range workspaces.Items {
+			if query.MatchesFilters(w) {
+				found = append(found, w)
+			}
+		}
+		if workspaces.Pagination == nil || workspaces.NextPage == 0 {
+			break
 		}
+		page = workspaces.NextPage
+	}
+
+	return paginateWorkspaces(found, pageNumber, 30), nil
+}
+
+// paginateWorkspaces returns the page of the workspaces, the first one if the
+// page number is -1.
+func paginateWorkspaces(workspaces []*tfe.Workspace, pageNumber, pageSize int) *tfe.WorkspaceList {
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	totalPages := (len(workspaces) + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	from := (pageNumber - 1) * pageSize
+	if from > len(workspaces) {
+		from = len(workspaces)
+	}
+	to := from + pageSize
+	if to > len(workspaces) {
+		to = len(workspaces)
+	}
+
+	return &tfe.WorkspaceList{
+		Pagination: &tfe.Pagination{
+			CurrentPage: pageNumber,
+			TotalPages:  totalPages,
+			TotalCount:  len(workspaces),
+		},
+		Items: workspaces[from:to],
 	}
-	return strings.TrimRight(b.String(), " "), tagsSearch
 }
 
-func (c *TFEClientImpl) ReadWorkspace(org, workspace string) (*tfe.Workspace, error) {
-	w, err := c.client.Workspaces.ReadWithOptions(context.Background(), org, workspace, &tfe.WorkspaceReadOptions{
+func (c *TFEClientImpl) ReadWorkspace(ctx context.Context, org, workspace string) (*tfe.Workspace, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	w, err := c.client.Workspaces.ReadWithOptions(ctx, org, workspace, &tfe.WorkspaceReadOptions{
 		Include: []tfe.WSIncludeOpt{"current_run", "current_run.plan", "locked_by"},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := c.client.Runs.ReadWithOptions(context.Background(), w.CurrentRun.ID, &tfe.RunReadOptions{
+	r, err := c.client.Runs.ReadWithOptions(ctx, w.CurrentRun.ID, &tfe.RunReadOptions{
 		Include: []tfe.RunIncludeOpt{"created_by", "plan", "apply"},
 	})
 	if err != nil {
@@ -115,8 +250,11 @@ func (c *TFEClientImpl) ReadWorkspace(org, workspace string) (*tfe.Workspace, er
 	return w, err
 }
 
-func (c *TFEClientImpl) ListWorkspaceTeamAccesses(workspaceID string) (*tfe.TeamAccessList, error) {
-	accesses, err := c.client.TeamAccess.List(context.Background(), &tfe.TeamAccessListOptions{
+func (c *TFEClientImpl) ListWorkspaceTeamAccesses(ctx context.Context, workspaceID string) (*tfe.TeamAccessList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	accesses, err := c.client.TeamAccess.List(ctx, &tfe.TeamAccessListOptions{
 		WorkspaceID: workspaceID,
 	})
 
@@ -125,7 +263,7 @@ func (c *TFEClientImpl) ListWorkspaceTeamAccesses(workspaceID string) (*tfe.Team
 	}
 
 	for _, a := range accesses.Items {
-		team, err := c.client.Teams.Read(context.Background(), a.Team.ID)
+		team, err := c.client.Teams.Read(ctx, a.Team.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -136,28 +274,52 @@ func (c *TFEClientImpl) ListWorkspaceTeamAccesses(workspaceID string) (*tfe.Team
 	return accesses, err
 }
 
-func (c *TFEClientImpl) ListWorkspaceVariables(workspaceID string) (*tfe.VariableList, error) {
-	return c.client.Variables.List(context.Background(), workspaceID, &tfe.VariableListOptions{})
+func (c *TFEClientImpl) ListWorkspaceVariables(ctx context.Context, workspaceID string, pageNumber int) (*tfe.VariableList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	options := tfe.ListOptions{PageSize: 30}
+	if pageNumber != -1 {
+		options.PageNumber = pageNumber
+	}
+
+	return c.client.Variables.List(ctx, workspaceID, &tfe.VariableListOptions{ListOptions: options})
 }
 
-func (c *TFEClientImpl) ListWorkspaceRuns(workspaceID string) (*tfe.RunList, error) {
+func (c *TFEClientImpl) ListWorkspaceRuns(ctx context.Context, workspaceID string) (*tfe.RunList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	options := &tfe.RunListOptions{Include: []tfe.RunIncludeOpt{"created_by"}}
-	return c.client.Runs.List(context.Background(), workspaceID, options)
+	return c.client.Runs.List(ctx, workspaceID, options)
 }
 
-func (c *TFEClientImpl) ReadWorkspaceRun(runID string) (*tfe.Run, error) {
-	options := &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{"plan", "apply"}}
-	return c.client.Runs.ReadWithOptions(context.Background(), runID, options)
+// ReadWorkspaceRun returns the run with its plan, its apply and its
+// workspace, the workspace with the name of its organization.
+func (c *TFEClientImpl) ReadWorkspaceRun(ctx context.Context, runID string) (*tfe.Run, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	options := &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{"plan", "apply", "workspace"}}
+	return c.client.Runs.ReadWithOptions(ctx, runID, options)
 }
 
-func (c *TFEClientImpl) ReadWorkspacePlan(planID string) (*tfe.Plan, error) {
-	return c.client.Plans.Read(context.Background(), planID)
+func (c *TFEClientImpl) ReadWorkspacePlan(ctx context.Context, planID string) (*tfe.Plan, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Plans.Read(ctx, planID)
 }
 
-func (c *TFEClientImpl) ReadWorkspacePlanLogs(planID string) (io.Reader, error) {
-	return c.client.Plans.Logs(context.Background(), planID)
+// ReadWorkspacePlanLogs returns a reader that follows the plan logs until the
+// plan finishes or the context is cancelled, so no request timeout applies.
+func (c *TFEClientImpl) ReadWorkspacePlanLogs(ctx context.Context, planID string) (io.Reader, error) {
+	return c.client.Plans.Logs(ctx, planID)
 }
 
-func (c *TFEClientImpl) ReadWorkspaceApplyLogs(planID string) (io.Reader, error) {
-	return c.client.Applies.Logs(context.Background(), planID)
+// ReadWorkspaceApplyLogs returns a reader that follows the apply logs until
+// the apply finishes or the context is cancelled, so no request timeout
+// applies.
+func (c *TFEClientImpl) ReadWorkspaceApplyLogs(ctx context.Context, planID string) (io.Reader, error) {
+	return c.client.Applies.Logs(ctx, planID)
 }
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// VariableOptions are the attributes of a workspace variable to create or
+// update. The category is only set when the variable is created, and a nil
+// value keeps the value of the variable updated, e.g. of sensitive variables,
+// which are write only.
+type VariableOptions struct {
+	Key         string
+	Value       *string
+	Description string
+	Category    tfe.CategoryType
+	HCL         bool
+	Sensitive   bool
+}
+
+func (c *TFEClientImpl) CreateVariable(ctx context.Context, workspaceID string, options VariableOptions) (*tfe.Variable, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Variables.Create(ctx, workspaceID, tfe.VariableCreateOptions{
+		Key:         tfe.String(options.Key),
+		Value:       options.Value,
+		Description: tfe.String(options.Description),
+		Category:    tfe.Category(options.Category),
+		HCL:         tfe.Bool(options.HCL),
+		Sensitive:   tfe.Bool(options.Sensitive),
+	})
+}
+
+func (c *TFEClientImpl) UpdateVariable(ctx context.Context, workspaceID, variableID string, options VariableOptions) (*tfe.Variable, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Variables.Update(ctx, workspaceID, variableID, tfe.VariableUpdateOptions{
+		Key:         tfe.String(options.Key),
+		Value:       options.Value,
+		Description: tfe.String(options.Description),
+		HCL:         tfe.Bool(options.HCL),
+		Sensitive:   tfe.Bool(options.Sensitive),
+	})
+}
+
+func (c *TFEClientImpl) DeleteVariable(ctx context.Context, workspaceID, variableID string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Variables.Delete(ctx, workspaceID, variableID)
+}
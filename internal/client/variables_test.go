@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateVariableKeepsValue(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/workspaces/ws-prod/vars/var-1":
+			assert.Equal(t, http.MethodPatch, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprint(w, `{"data": {"id": "var-1", "type": "vars", "attributes": {"key": "db_password", "sensitive": true}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCredentialsEnv(t, map[string]string{"TFE_ADDRESS": server.URL, "TFE_TOKEN": "test-token"}, "")
+	c, err := NewTFEClient(Options{})
+	require.NoError(t, err)
+
+	_, err = c.UpdateVariable(context.Background(), "ws-prod", "var-1", VariableOptions{
+		Key:       "db_password",
+		Sensitive: true,
+	})
+	require.NoError(t, err)
+
+	// the value is not sent, so the sensitive value is kept
+	assert.Equal(t, map[string]interface{}{
+		"key":         "db_password",
+		"description": "",
+		"hcl":         false,
+		"sensitive":   true,
+	}, body["data"].(map[string]interface{})["attributes"])
+}
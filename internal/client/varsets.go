@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/jsonapi"
+)
+
+const variableSetsPageSize = 30
+
+// ListVariableSets returns the variable sets of the organization, with the
+// workspaces they are applied to and their variables IDs.
+func (c *TFEClientImpl) ListVariableSets(ctx context.Context, org string, pageNumber int) (*tfe.VariableSetList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	options := tfe.ListOptions{PageSize: variableSetsPageSize}
+	if pageNumber != -1 {
+		options.PageNumber = pageNumber
+	}
+
+	return c.client.VariableSets.List(ctx, org, &tfe.VariableSetListOptions{
+		Include:     fmt.Sprintf("%s,%s", tfe.VariableSetWorkspaces, tfe.VariableSetVars),
+		ListOptions: options,
+	})
+}
+
+func (c *TFEClientImpl) ListVariableSetVariables(ctx context.Context, variableSetID string, pageNumber int) (*tfe.VariableSetVariableList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	options := tfe.ListOptions{PageSize: variableSetsPageSize}
+	if pageNumber != -1 {
+		options.PageNumber = pageNumber
+	}
+
+	return c.client.VariableSetVariables.List(ctx, variableSetID, &tfe.VariableSetVariableListOptions{
+		ListOptions: options,
+	})
+}
+
+type variableSetsMeta struct {
+	Meta struct {
+		Pagination *tfe.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+// ListWorkspaceVariableSets returns the variable sets applied to the
+// workspace, the global ones included, with all their variables. The go-tfe
+// version used does not list the variable sets of a workspace, so they are
+// read directly from the API.
+func (c *TFEClientImpl) ListWorkspaceVariableSets(ctx context.Context, workspaceID string) ([]*tfe.VariableSet, error) {
+	applied := []*tfe.VariableSet{}
+	for page := 1; ; page++ {
+		sets, pagination, err := c.listWorkspaceVariableSetsPage(ctx, workspaceID, page)
+		if err != nil {
+			return nil, fmt.Errorf("error listing the workspace variable sets: %w", err)
+		}
+
+		applied = append(applied, sets...)
+		if pagination == nil || pagination.NextPage == 0 {
+			return applied, nil
+		}
+	}
+}
+
+func (c *TFEClientImpl) listWorkspaceVariableSetsPage(ctx context.Context, workspaceID string, pageNumber int) ([]*tfe.VariableSet, *tfe.Pagination, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("include", string(tfe.VariableSetVars))
+	query.Set("page[number]", strconv.Itoa(pageNumber))
+	query.Set("page[size]", strconv.Itoa(variableSetsPageSize))
+
+	path := fmt.Sprintf("workspaces/%s/varsets?%s", url.PathEscape(workspaceID), query.Encode())
+	content, err := c.doAPI(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(bytes.NewReader(content), reflect.TypeOf(&tfe.VariableSet{}))
+	if err != nil {
+		return nil, nil, err
+	}
+	meta := variableSetsMeta{}
+	if err := json.Unmarshal(content, &meta); err != nil {
+		return nil, nil, err
+	}
+
+	sets := []*tfe.VariableSet{}
+	for _, item := range items {
+		sets = append(sets, item.(*tfe.VariableSet))
+	}
+	return sets, meta.Meta.Pagination, nil
+}
+
+// VariableSetApplied returns true if the variable set is global or applied to
+// the workspace.
+func VariableSetApplied(s *tfe.VariableSet, workspaceID string) bool {
+	if s.Global {
+		return true
+	}
+	for _, w := range s.Workspaces {
+		if w.ID == workspaceID {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveVariable is a variable of the workspace, or of a variable set
+// applied to it, with the variable taking precedence over it if it is
+// overridden.
+type EffectiveVariable struct {
+	Key         string
+	Value       string
+	Description string
+	Category    tfe.CategoryType
+	HCL         bool
+	Sensitive   bool
+
+	// VariableSet is the variable set of the variable, nil for the workspace
+	// variables.
+	VariableSet *tfe.VariableSet
+
+	// OverriddenBy is the variable the runs receive instead of this one, nil
+	// if the runs receive this one.
+	OverriddenBy *EffectiveVariable
+}
+
+// Source returns where the variable is defined, the workspace or a variable
+// set.
+func (v *EffectiveVariable) Source() string {
+	switch {
+	case v.VariableSet == nil:
+		return "workspace"
+	case v.VariableSet.Global:
+		return fmt.Sprintf("%s (global set)", v.VariableSet.Name)
+	}
+	return fmt.Sprintf("%s (set)", v.VariableSet.Name)
+}
+
+// precedence ranks the variable sources, the lowest rank wins: the workspace
+// variables, then the variable sets applied to the workspace and then the
+// global variable sets.
+func (v *EffectiveVariable) precedence() int {
+	switch {
+	case v.VariableSet == nil:
+		return 0
+	case !v.VariableSet.Global:
+		return 1
+	}
+	return 2
+}
+
+// ResolveVariables merges the workspace variables with the variables of the
+// variable sets applied to it, marking the variables overridden by another
+// with the same key and category. Between variable sets of the same kind the
+// set with the lexically first name wins. The variables are sorted by
+// category and key, the one the runs receive first.
+func ResolveVariables(variables []*tfe.Variable, sets []*tfe.VariableSet) []*EffectiveVariable {
+	all := []*EffectiveVariable{}
+	for _, v := range variables {
+		all = append(all, &EffectiveVariable{
+			Key:         v.Key,
+			Value:       v.Value,
+			Description: v.Description,
+			Category:    v.Category,
+			HCL:         v.HCL,
+			Sensitive:   v.Sensitive,
+		})
+	}
+	for _, s := range sets {
+		for _, v := range s.Variables {
+			all = append(all, &EffectiveVariable{
+				Key:         v.Key,
+				Value:       v.Value,
+				Description: v.Description,
+				Category:    v.Category,
+				HCL:         v.HCL,
+				Sensitive:   v.Sensitive,
+				VariableSet: s,
+			})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.precedence() != b.precedence() {
+			return a.precedence() < b.precedence()
+		}
+		return a.VariableSet != nil && b.VariableSet != nil && a.VariableSet.Name < b.VariableSet.Name
+	})
+
+	var winner *EffectiveVariable
+	for _, v := range all {
+		if winner != nil && winner.Key == v.Key && winner.Category == v.Category {
+			v.OverriddenBy = winner
+			continue
+		}
+		winner = v
+	}
+
+	return all
+}
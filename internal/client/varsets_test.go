@@ -0,0 +1,67 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveVariables(t *testing.T) {
+	variables := []*tfe.Variable{
+		{Key: "region", Value: "eu-west-1", Category: tfe.CategoryTerraform},
+		{Key: "AWS_REGION", Value: "eu-west-1", Category: tfe.CategoryEnv},
+	}
+	global := &tfe.VariableSet{
+		Name:   "aws-credentials",
+		Global: true,
+		Variables: []*tfe.VariableSetVariable{
+			{Key: "AWS_REGION", Value: "us-east-1", Category: tfe.CategoryEnv},
+			{Key: "AWS_ACCESS_KEY_ID", Value: "AKIA", Category: tfe.CategoryEnv},
+			{Key: "instance_type", Value: "t3.micro", Category: tfe.CategoryTerraform},
+		},
+	}
+	prod := &tfe.VariableSet{
+		Name: "prod-defaults",
+		Variables: []*tfe.VariableSetVariable{
+			{Key: "region", Value: "us-east-1", Category: tfe.CategoryTerraform},
+			{Key: "instance_type", Value: "t3.large", Category: tfe.CategoryTerraform},
+			// same key, other category: not an override
+			{Key: "AWS_ACCESS_KEY_ID", Value: "AKIA", Category: tfe.CategoryTerraform},
+		},
+	}
+	alpha := &tfe.VariableSet{
+		Name: "alpha-defaults",
+		Variables: []*tfe.VariableSetVariable{
+			{Key: "instance_type", Value: "t3.small", Category: tfe.CategoryTerraform},
+		},
+	}
+
+	resolved := ResolveVariables(variables, []*tfe.VariableSet{global, prod, alpha})
+	require.Len(t, resolved, 9)
+
+	type row struct {
+		key, value, source, overriddenBy string
+	}
+	rows := []row{}
+	for _, v := range resolved {
+		r := row{key: v.Key, value: v.Value, source: v.Source()}
+		if v.OverriddenBy != nil {
+			r.overriddenBy = v.OverriddenBy.Source()
+		}
+		rows = append(rows, r)
+	}
+
+	assert.Equal(t, []row{
+		{"AWS_ACCESS_KEY_ID", "AKIA", "aws-credentials (global set)", ""},
+		{"AWS_REGION", "eu-west-1", "workspace", ""},
+		{"AWS_REGION", "us-east-1", "aws-credentials (global set)", "workspace"},
+		{"AWS_ACCESS_KEY_ID", "AKIA", "prod-defaults (set)", ""},
+		{"instance_type", "t3.small", "alpha-defaults (set)", ""},
+		{"instance_type", "t3.large", "prod-defaults (set)", "alpha-defaults (set)"},
+		{"instance_type", "t3.micro", "aws-credentials (global set)", "alpha-defaults (set)"},
+		{"region", "eu-west-1", "workspace", ""},
+		{"region", "us-east-1", "prod-defaults (set)", "workspace"},
+	}, rows)
+}
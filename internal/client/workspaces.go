@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/jsonapi"
+)
+
+type workspacesDocument struct {
+	Data []struct {
+		ID            string `json:"id"`
+		Relationships struct {
+			Project struct {
+				Data *struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			} `json:"project"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Meta struct {
+		Pagination *tfe.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+// listWorkspacesPage lists a page of the workspaces, the first one if the
+// page number is 0. The go-tfe version used neither sends the project and
+// excluded tags filters nor decodes the project relationship, so the page is
+// read directly from the API, and the project IDs are added to projectIDs,
+// by workspace ID, unless it is nil.
+func (c *TFEClientImpl) listWorkspacesPage(ctx context.Context, org string, params url.Values, pageNumber, pageSize int, projectIDs map[string]string) (*tfe.WorkspaceList, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	query.Set("page[size]", strconv.Itoa(pageSize))
+	if pageNumber > 0 {
+		query.Set("page[number]", strconv.Itoa(pageNumber))
+	}
+
+	path := fmt.Sprintf("organizations/%s/workspaces?%s", url.PathEscape(org), query.Encode())
+	content, err := c.doAPI(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(bytes.NewReader(content), reflect.TypeOf(&tfe.Workspace{}))
+	if err != nil {
+		return nil, err
+	}
+	doc := workspacesDocument{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	workspaces := &tfe.WorkspaceList{Pagination: doc.Meta.Pagination}
+	for _, item := range items {
+		workspaces.Items = append(workspaces.Items, item.(*tfe.Workspace))
+	}
+	if projectIDs != nil {
+		for _, d := range doc.Data {
+			if d.Relationships.Project.Data != nil {
+				projectIDs[d.ID] = d.Relationships.Project.Data.ID
+			}
+		}
+	}
+	return workspaces, nil
+}
@@ -9,19 +9,40 @@ import (
 )
 
 type Config struct {
+	Hostname               string `json:"hostname,omitempty"`
 	Organization           string `json:"organization"`
 	Workspace              string `json:"workspace"`
 	WorkspaceShowVariables bool   `json:"workspace_show_vars"`
+	RequestTimeout         int    `json:"request_timeout"` // seconds
+	AutoRefresh            bool   `json:"auto_refresh"`
+	RefreshInterval        int    `json:"refresh_interval"` // seconds
+	MonochromeLogs         bool   `json:"monochrome_logs"`
+
+	Profiles       []*Profile `json:"profiles,omitempty"`
+	CurrentProfile string     `json:"current_profile,omitempty"`
 
 	RunID string
 }
 
+// Profile is a named connection to a Terraform Cloud/Enterprise instance.
+type Profile struct {
+	Name         string `json:"name"`
+	Hostname     string `json:"hostname,omitempty"`
+	TokenEnv     string `json:"token_env,omitempty"`
+	Organization string `json:"organization,omitempty"`
+
+	WorkspaceShowVariables *bool `json:"workspace_show_vars,omitempty"`
+}
+
 const configDirectory = ".config/terrui"
 const configFile = "terrui.json"
 
 func NewConfig() (*Config, error) {
 	c := &Config{
 		WorkspaceShowVariables: true,
+		RequestTimeout:         30,
+		AutoRefresh:            true,
+		RefreshInterval:        5,
 	}
 	err := c.Load()
 
@@ -79,6 +100,45 @@ func (c *Config) Save() error {
 	return nil
 }
 
+// ActiveProfile returns the profile in use or nil if no profile is selected.
+func (c *Config) ActiveProfile() *Profile {
+	return c.FindProfile(c.CurrentProfile)
+}
+
+// FindProfile returns the profile with the given name or nil if it does not
+// exist.
+func (c *Config) FindProfile(name string) *Profile {
+	if name == "" {
+		return nil
+	}
+
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// UseProfile makes the given profile the active one, resetting the
+// organization and workspace to the profile defaults.
+func (c *Config) UseProfile(name string) error {
+	p := c.FindProfile(name)
+	if p == nil {
+		return fmt.Errorf("profile %s not found", name)
+	}
+
+	c.CurrentProfile = p.Name
+	c.Organization = p.Organization
+	c.Workspace = ""
+	c.RunID = ""
+	if p.WorkspaceShowVariables != nil {
+		c.WorkspaceShowVariables = *p.WorkspaceShowVariables
+	}
+
+	return nil
+}
+
 func buildConfigFilePath() (string, error) {
 	userHome, err := os.UserHomeDir()
 	if err != nil {
@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/renato0307/terrui/internal/client"
 	"github.com/renato0307/terrui/internal/config"
 	"github.com/rivo/tview"
 )
@@ -20,22 +23,69 @@ type App struct {
 	currentPage Page
 	actions     KeyActions
 
-	header *Header
-	footer *Footer
+	// pageCtx is cancelled when the current page changes, stopping its
+	// in-flight requests.
+	pageCtx    context.Context
+	pageCancel context.CancelFunc
+
+	history *History
+	current *historyEntry
+
+	// refreshing is set while the auto refresh is loading the current page
+	refreshing bool
+	// queuedReload reloads the page once the refresh running is done
+	queuedReload func()
+
+	tfeClient client.TFEClient
+	// clientErr is why the TFE client of the active profile could not be
+	// created, e.g. no token was found. Only the profiles and the help are
+	// shown until switching to another profile.
+	clientErr error
+
+	header  *Header
+	footer  *Footer
+	command *CommandPrompt
+	dialog  *ConfirmDialog
 
 	config *config.Config
 }
 
-func NewApp() *App {
-	app := tview.NewApplication()
-	a := &App{}
-
+func NewApp(profile string) (*App, error) {
 	config, err := config.NewConfig()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	header := NewHeader().SetCrumb([]string{})
+	if profile != "" {
+		if err := config.UseProfile(profile); err != nil {
+			return nil, err
+		}
+		if err := config.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	tfeClient, err := client.NewTFEClient(clientOptions(config, config.ActiveProfile()))
+	if err != nil {
+		return newApp(config, nil, fmt.Errorf("error creating the TFE client: %w", err)), nil
+	}
+
+	return NewAppWithClient(config, tfeClient), nil
+}
+
+// NewAppWithClient creates the application using the given configuration and
+// TFE client, shared by all the pages.
+func NewAppWithClient(config *config.Config, tfeClient client.TFEClient) *App {
+	return newApp(config, tfeClient, nil)
+}
+
+// newApp creates the application, showing the profiles if the TFE client
+// could not be created.
+func newApp(config *config.Config, tfeClient client.TFEClient, clientErr error) *App {
+	app := tview.NewApplication()
+	a := &App{}
+
+	header := NewHeader().SetProfile(config.CurrentProfile).SetCrumb([]string{})
 	pages := tview.NewPages()
 	pages.SetBorderPadding(0, 0, 1, 1)
 	footer := NewFooter(a, "welcome 🤓 - press ? for help", tview.Styles.PrimaryTextColor, 3)
@@ -49,15 +99,22 @@ func NewApp() *App {
 		AddItem(footer, 2, 0, 1, 1, 0, 0, false)
 
 	a.Application = app
+	a.pageCtx, a.pageCancel = context.WithCancel(context.Background())
 	a.config = config
+	a.tfeClient = tfeClient
+	a.clientErr = clientErr
 	a.layout = layout
 	a.pages = pages
 	a.header = header
 	a.footer = footer
+	a.command = NewCommandPrompt(a)
 	a.pagesMap = initPages()
+	a.history = &History{}
 	a.actions = a.bindKeys()
 
-	if config.Workspace != "" {
+	if clientErr != nil {
+		a.activatePage(ProfilesPageName, nil, false)
+	} else if config.Workspace != "" {
 		a.activatePage(WorkspacePageName, nil, false)
 	} else if config.Organization != "" {
 		a.activatePage(WorkspacesPageName, nil, false)
@@ -69,6 +126,9 @@ func NewApp() *App {
 }
 
 func (a *App) Run() error {
+	stopRefresh := a.startAutoRefresh()
+	defer stopRefresh()
+
 	return a.SetRoot(a.layout, true).SetFocus(a.pages).Run()
 }
 
@@ -79,13 +139,24 @@ func initPages() map[string]PageFactory {
 	pagesMap[WorkspacePageName] = NewWorkspacePage
 	pagesMap[RunPageName] = NewRunPage
 	pagesMap[HelpPageName] = NewHelpPage
+	pagesMap[ProfilesPageName] = NewProfilesPage
+	pagesMap[VariablesPageName] = NewVariablesPage
+	pagesMap[TeamsPageName] = NewTeamsPage
+	pagesMap[PlanChangesPageName] = NewPlanChangesPage
+	pagesMap[StateVersionsPageName] = NewStateVersionsPage
+	pagesMap[RunBuilderPageName] = NewRunBuilderPage
+	pagesMap[VariableSetsPageName] = NewVariableSetsPage
+	pagesMap[EffectiveVariablesPageName] = NewEffectiveVariablesPage
+	pagesMap[VariablesImportPageName] = NewVariablesImportPage
 
 	return pagesMap
 }
 
+// activatePage navigates to a new page, recording the current one in the
+// history.
 func (a *App) activatePage(name string, page Page, skipLoad bool) {
-	if a.pages.HasPage(name) {
-		a.pages.RemovePage(name)
+	if !a.clientAvailable(name) {
+		return
 	}
 
 	if page == nil {
@@ -94,7 +165,56 @@ func (a *App) activatePage(name string, page Page, skipLoad bool) {
 			a.footer.ShowError(fmt.Sprintf("😵 page %s not configured", name))
 			return
 		}
-		page = pageFactory(a)
+		page = pageFactory(a, a.tfeClient)
+	}
+
+	if a.current != nil {
+		a.current.focus = a.GetFocus()
+		a.history.Push(a.current)
+	}
+	a.current = newHistoryEntry(name, page, a.config)
+
+	a.showPage(name, page)
+	a.ExecPage(page, skipLoad)
+}
+
+// clientAvailable returns true if the page can be shown, i.e. the TFE client
+// was created or the page does not need it, and shows the error otherwise.
+func (a *App) clientAvailable(name string) bool {
+	if a.clientErr == nil || name == ProfilesPageName || name == HelpPageName {
+		return true
+	}
+
+	a.footer.ShowError(fmt.Sprintf("😵 %s, press ctrl-p to switch profiles", a.clientErr))
+	return false
+}
+
+// restorePage shows a page from the history as it was left, only loading it
+// if it was never rendered.
+func (a *App) restorePage(e *historyEntry) {
+	if a.current != nil {
+		a.current.focus = a.GetFocus()
+	}
+	a.current = e
+	e.restore(a.config)
+
+	a.showPage(e.name, e.page)
+	if !e.rendered {
+		a.ExecPage(e.page, false)
+		return
+	}
+
+	a.header.SetCrumb(e.page.Crumb())
+	if e.focus != nil {
+		a.SetFocus(e.focus)
+	} else {
+		a.SetFocus(e.page)
+	}
+}
+
+func (a *App) showPage(name string, page Page) {
+	if a.pages.HasPage(name) {
+		a.pages.RemovePage(name)
 	}
 
 	if page.Name() != HelpPageName {
@@ -105,49 +225,84 @@ func (a *App) activatePage(name string, page Page, skipLoad bool) {
 
 	a.pages.AddAndSwitchToPage(name, page, true)
 
-	if page.Footer() != "" {
+	switch {
+	case a.clientErr != nil:
+		a.footer.ShowText(fmt.Sprintf("😵 %s, press ctrl-p to switch profiles", a.clientErr))
+	case page.Footer() != "":
 		a.footer.ShowText(page.Footer())
-	} else {
+	default:
 		a.footer.ShowText(defaultFooter)
 	}
 
 	a.currentPage = page
 
-	go a.ExecPage(page, skipLoad)
+	a.pageCancel()
+	a.pageCtx, a.pageCancel = context.WithCancel(context.Background())
 }
 
 func (a *App) ExecPage(p Page, skipLoad bool) {
 	a.ExecPageWithLoadFunc(p, p.Load, skipLoad)
 }
 
-func (a *App) ExecPageWithLoadFunc(p Page, loadFn func() error, skipLoad bool) {
-	a.QueueUpdateDraw(func() {
-		a.header.SetCrumb(p.Crumb())
-		a.ShowLoading()
-	})
+// ExecPageWithLoadFunc loads the page in the background and renders it once
+// loaded. Results are discarded if the page changed in the meantime.
+func (a *App) ExecPageWithLoadFunc(p Page, loadFn func(context.Context) error, skipLoad bool) {
+	ctx := a.pageCtx
 
-	a.QueueUpdateDraw(func() {
-		a.SetFocus(p)
+	go func() {
+		a.QueueUpdateDraw(func() {
+			a.header.SetCrumb(p.Crumb())
+			a.ShowLoading()
+		})
 
+		var err error
 		if !skipLoad {
-			err := loadFn()
+			err = loadFn(ctx)
+		}
+
+		a.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+
 			if err != nil {
 				a.footer.ShowError(fmt.Sprintf("😵 %s", err.Error()))
 				return
 			}
-		}
-		msg := p.View()
 
-		if msg != "" {
-			a.footer.Show(fmt.Sprintf("✅ %s", msg), tview.Styles.SecondaryTextColor)
-		} else {
-			a.footer.ShowText(p.Footer())
-		}
+			if a.current != nil && a.current.page == p {
+				a.current.rendered = true
+			}
+
+			a.SetFocus(p)
+			msg := p.View()
 
-	})
+			if msg != "" {
+				a.footer.Show(fmt.Sprintf("✅ %s", msg), tview.Styles.SecondaryTextColor)
+			} else {
+				a.footer.ShowText(p.Footer())
+			}
+		})
+	}()
 }
 
 func (a *App) appKeyboard(evt *tcell.EventKey) *tcell.EventKey {
+	// Ctrl-C quits from anywhere, even while typing
+	if evt.Key() == tcell.KeyCtrlC {
+		return a.quit(evt)
+	}
+
+	// the confirmation dialogs handle their keys
+	if a.dialog != nil {
+		return evt
+	}
+
+	// input fields, like the search and the command prompt, need all the keys
+	switch a.GetFocus().(type) {
+	case *tview.InputField, *CommandPrompt:
+		return evt
+	}
+
 	key := AsKey(evt)
 	action, ok := a.actions[key]
 	if ok {
@@ -158,13 +313,24 @@ func (a *App) appKeyboard(evt *tcell.EventKey) *tcell.EventKey {
 
 func (a *App) bindKeys() KeyActions {
 	return KeyActions{
-		tcell.KeyCtrlO: NewSharedKeyAction("list organizations", a.listOrgs, true),
-		tcell.KeyCtrlC: NewSharedKeyAction("quit", a.quit, true),
-		KeyHelp:        NewSharedKeyAction("help", a.showHelp, true),
+		tcell.KeyCtrlO:      NewSharedKeyAction("list organizations", a.listOrgs, true),
+		tcell.KeyCtrlP:      NewSharedKeyAction("list profiles", a.listProfiles, true),
+		tcell.KeyCtrlR:      NewSharedKeyAction("toggle auto refresh", a.toggleAutoRefresh, true),
+		tcell.KeyCtrlC:      NewSharedKeyAction("quit", a.quit, true),
+		KeyHelp:             NewSharedKeyAction("help", a.showHelp, true),
+		KeyColon:            NewSharedKeyAction("command mode", a.showCommand, true),
+		tcell.KeyEsc:        NewSharedKeyAction("go back", a.goBack, true),
+		tcell.KeyBackspace2: NewSharedKeyAction("go back", a.goBack, false),
+		KeyLeftBracket:      NewSharedKeyAction("go back", a.goBack, false),
+		KeyRightBracket:     NewSharedKeyAction("go forward", a.goForward, true),
 	}
 }
 
 func (a *App) listOrgs(ek *tcell.EventKey) *tcell.EventKey {
+	if !a.clientAvailable(OrganizationsPageName) {
+		return nil
+	}
+
 	a.config.Organization = ""
 	a.config.Save()
 	a.activatePage(OrganizationsPageName, nil, false)
@@ -172,20 +338,114 @@ func (a *App) listOrgs(ek *tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (a *App) listProfiles(ek *tcell.EventKey) *tcell.EventKey {
+	a.activatePage(ProfilesPageName, nil, false)
+
+	return nil
+}
+
+func (a *App) switchProfile(name string) {
+	profile := a.config.FindProfile(name)
+	if profile == nil {
+		a.footer.ShowError(fmt.Sprintf("😵 profile %s not found", name))
+		return
+	}
+
+	a.ShowLoading()
+	go func() {
+		tfeClient, err := client.NewTFEClient(clientOptions(a.config, profile))
+		if err != nil {
+			a.footer.ShowError(fmt.Sprintf("😵 error creating the TFE client: %s", err.Error()))
+			return
+		}
+
+		a.QueueUpdateDraw(func() {
+			a.tfeClient = tfeClient
+			a.clientErr = nil
+			a.history.Clear()
+			a.current = nil
+			a.config.UseProfile(name)
+			a.config.Save()
+			a.header.SetProfile(a.config.CurrentProfile)
+
+			if a.config.Organization != "" {
+				a.activatePage(WorkspacesPageName, nil, false)
+			} else {
+				a.activatePage(OrganizationsPageName, nil, false)
+			}
+		})
+	}()
+}
+
+func (a *App) showCommand(ek *tcell.EventKey) *tcell.EventKey {
+	a.command.Open()
+	return nil
+}
+
+// exit exits the process, replaced by the tests.
+var exit = os.Exit
+
 func (a *App) quit(ek *tcell.EventKey) *tcell.EventKey {
 	a.Stop()
-	os.Exit(0)
+	exit(0)
 
 	return nil
 }
 
 func (a *App) showHelp(ek *tcell.EventKey) *tcell.EventKey {
-	currentPage := a.currentPage
 	a.activatePage(HelpPageName, nil, false)
-	a.currentPage = currentPage
 	return nil
 }
 
+// goBack returns to the previous page. Without history, e.g. right after
+// starting, it goes up to the parent page instead.
+func (a *App) goBack(ek *tcell.EventKey) *tcell.EventKey {
+	e := a.history.Back(a.current)
+	if e == nil {
+		a.goUp()
+		return nil
+	}
+
+	a.restorePage(e)
+	return nil
+}
+
+func (a *App) goForward(ek *tcell.EventKey) *tcell.EventKey {
+	e := a.history.Forward(a.current)
+	if e != nil {
+		a.restorePage(e)
+	}
+	return nil
+}
+
+func (a *App) goUp() {
+	if a.currentPage == nil {
+		return
+	}
+
+	switch a.currentPage.Name() {
+	case RunPageName, VariablesPageName, TeamsPageName, StateVersionsPageName, RunBuilderPageName,
+		VariableSetsPageName, EffectiveVariablesPageName:
+		a.activatePage(WorkspacePageName, nil, false)
+	case VariableSetVariablesPageName:
+		a.activatePage(VariableSetsPageName, nil, false)
+	case PlanChangesPageName:
+		a.activatePage(RunPageName, nil, false)
+	case PlanChangePageName:
+		a.activatePage(PlanChangesPageName, nil, false)
+	case VariablePageName, VariablesImportPageName:
+		a.activatePage(VariablesPageName, nil, false)
+	case StatePageName, StateDiffPageName:
+		a.activatePage(StateVersionsPageName, nil, false)
+	case WorkspacePageName:
+		a.config.Workspace = ""
+		a.config.Save()
+		a.activatePage(WorkspacesPageName, nil, false)
+	case WorkspacesPageName:
+		a.listOrgs(nil)
+	}
+}
+
 func AsKey(evt *tcell.EventKey) tcell.Key {
 	if evt.Key() != tcell.KeyRune {
 		return evt.Key()
@@ -197,6 +457,21 @@ func AsKey(evt *tcell.EventKey) tcell.Key {
 	return key
 }
 
+func clientOptions(c *config.Config, p *config.Profile) client.Options {
+	if p != nil {
+		return client.Options{
+			Hostname:       p.Hostname,
+			PreferHostname: true,
+			TokenEnv:       p.TokenEnv,
+			Timeout:        time.Duration(c.RequestTimeout) * time.Second,
+		}
+	}
+	return client.Options{
+		Hostname: c.Hostname,
+		Timeout:  time.Duration(c.RequestTimeout) * time.Second,
+	}
+}
+
 func (a *App) ShowLoading() {
 	a.footer.Show("⏳ loading...", tview.Styles.SecondaryTextColor)
 }
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestAppWithoutClient(t *testing.T) {
+	cfg := &config.Config{
+		Organization:   "acme",
+		Workspace:      "app-prod",
+		CurrentProfile: "private",
+		Profiles:       []*config.Profile{{Name: "saas"}, {Name: "private", Hostname: "tfe.example.com"}},
+	}
+	h := startHarness(t, cfg, nil, func() *App {
+		return newApp(cfg, nil, errors.New("error creating the TFE client: no token found for tfe.example.com"))
+	})
+
+	h.waitFor("tfe.example.com")
+	h.onUI(func() {
+		// shown once the loading message is gone
+		assert.Equal(t, "😵 error creating the TFE client: no token found for tfe.example.com, press ctrl-p to switch profiles", h.app.footer.previousText)
+	})
+
+	h.pressKey(tcell.KeyCtrlO)
+	h.waitFor("ERROR: 😵 error creating the TFE client")
+	h.onUI(func() {
+		assert.Equal(t, ProfilesPageName, h.app.currentPage.Name())
+		assert.Equal(t, "acme", h.config.Organization)
+	})
+
+	h.pressRune('?')
+	h.waitFor("list profiles")
+}
+
+func TestQuitWhileTyping(t *testing.T) {
+	exited := make(chan int, 1)
+	original := exit
+	exit = func(code int) {
+		exited <- code
+	}
+	t.Cleanup(func() {
+		exit = original
+	})
+
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('/')
+	h.typeText("app")
+	h.pressKey(tcell.KeyCtrlC)
+
+	select {
+	case code := <-exited:
+		assert.Equal(t, 0, code)
+	case <-time.After(harnessTimeout):
+		t.Fatal("Ctrl-C did not quit while typing")
+	}
+	<-h.stopped
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v2"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const PlanChangePageName string = "change"
+
+// sensitiveValue replaces the sensitive values, like terraform does.
+const sensitiveValue = "(sensitive value)"
+
+// PlanChangePage shows the attribute diff of a planned resource change, with
+// the sensitive values masked.
+type PlanChangePage struct {
+	*tview.TextView
+
+	app    *App
+	change client.ResourceChange
+}
+
+type planChangeInfo struct {
+	Address  string `yaml:"Address"`
+	Action   string `yaml:"Action"`
+	Reason   string `yaml:"Reason,omitempty"`
+	Provider string `yaml:"Provider"`
+}
+
+func NewPlanChangePage(app *App, change client.ResourceChange) Page {
+	return &PlanChangePage{
+		TextView: tview.NewTextView(),
+		app:      app,
+		change:   change,
+	}
+}
+
+func (c *PlanChangePage) Load(ctx context.Context) error {
+	return nil
+}
+
+func (c *PlanChangePage) View() string {
+	info := planChangeInfo{
+		Address:  c.change.Address,
+		Action:   c.change.Change.Action(),
+		Reason:   c.change.ActionReason,
+		Provider: c.change.ProviderName,
+	}
+	yamlData, _ := yaml.Marshal(info)
+
+	change := c.change.Change
+	diff := diffChange(
+		diffSide{value: change.Before, sensitive: change.BeforeSensitive},
+		diffSide{value: change.After, sensitive: change.AfterSensitive, unknown: change.AfterUnknown})
+
+	text := []string{colorizeYAML(string(yamlData))}
+	for _, l := range diff {
+		text = append(text, l.colorize())
+	}
+
+	c.SetBorder(true)
+	c.SetBorderPadding(0, 1, 1, 1)
+	c.SetTitle(" change ")
+	c.SetDynamicColors(true)
+	c.SetText(strings.Join(text, "\n"))
+
+	return ""
+}
+
+// maskSensitive returns the value with the sensitive values replaced, where
+// sensitive is true for a sensitive value or has the shape of the value with
+// the sensitive attributes true.
+func maskSensitive(value, sensitive interface{}) interface{} {
+	switch s := sensitive.(type) {
+	case bool:
+		if s {
+			return sensitiveValue
+		}
+	case map[string]interface{}:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		masked := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			masked[k] = maskSensitive(v, s[k])
+		}
+		return masked
+	case []interface{}:
+		l, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		masked := make([]interface{}, len(l))
+		for i, v := range l {
+			var sv interface{}
+			if i < len(s) {
+				sv = s[i]
+			}
+			masked[i] = maskSensitive(v, sv)
+		}
+		return masked
+	}
+	return value
+}
+
+func (c *PlanChangePage) BindKeys() KeyActions {
+	return KeyActions{}
+}
+
+func (c *PlanChangePage) Crumb() []string {
+	return []string{
+		c.app.config.Organization,
+		c.app.config.Workspace,
+		"runs",
+		c.app.config.RunID,
+		PlanChangesPageName,
+		c.change.Address,
+	}
+}
+
+func (c *PlanChangePage) Name() string {
+	return PlanChangePageName
+}
+
+func (c *PlanChangePage) Footer() string {
+	return ""
+}
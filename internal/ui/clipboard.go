@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands are the commands copying their input to the system
+// clipboard, tried in order.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copyToClipboard copies the text to the system clipboard, the tests replace
+// it to avoid touching the clipboard.
+var copyToClipboard = func(text string) error {
+	for _, c := range clipboardCommands {
+		path, err := exec.LookPath(c[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("error running %s: %w", c[0], err)
+		}
+		return nil
+	}
+
+	return errors.New("no clipboard command found, install xclip, xsel or wl-copy")
+}
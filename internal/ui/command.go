@@ -0,0 +1,369 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// command is an action available in the command prompt, e.g. `:ws app-prod`.
+type command struct {
+	aliases     []string
+	description string
+	run         func(args []string) error
+	complete    func(arg string) []string
+}
+
+// CommandPrompt replaces the footer with an input field accepting k9s style
+// commands, with tab completion for the command aliases and their arguments.
+type CommandPrompt struct {
+	*tview.InputField
+
+	app      *App
+	commands []*command
+
+	// workspaces caches the workspace names of the current organization to
+	// complete the `:ws` argument.
+	workspaces []string
+}
+
+func NewCommandPrompt(app *App) *CommandPrompt {
+	c := &CommandPrompt{
+		InputField: tview.NewInputField(),
+		app:        app,
+	}
+	c.commands = c.initCommands()
+
+	c.SetLabel(":")
+	c.SetFieldBackgroundColor(tview.Styles.PrimitiveBackgroundColor)
+	c.SetAutocompleteFunc(c.autocomplete)
+	c.SetDoneFunc(c.done)
+	c.SetInputCapture(c.keyboard)
+
+	return c
+}
+
+func (c *CommandPrompt) initCommands() []*command {
+	return []*command{
+		{
+			aliases:     []string{"orgs", "org", "organizations"},
+			description: "list organizations",
+			run:         c.runOrgs,
+		},
+		{
+			aliases:     []string{"ws", "workspaces", "workspace"},
+			description: "list workspaces or open the named workspace",
+			run:         c.runWorkspaces,
+			complete:    c.completeWorkspaces,
+		},
+		{
+			aliases:     []string{"run", "runs"},
+			description: "open the run with the given ID",
+			run:         c.runRun,
+		},
+		{
+			aliases:     []string{"vars", "variables"},
+			description: "list the workspace variables",
+			run:         c.workspacePage(VariablesPageName),
+		},
+		{
+			aliases:     []string{"varsets", "variablesets"},
+			description: "list the organization variable sets",
+			run:         c.workspacePage(VariableSetsPageName),
+		},
+		{
+			aliases:     []string{"effective", "effectivevars"},
+			description: "list the variables the workspace runs receive",
+			run:         c.workspacePage(EffectiveVariablesPageName),
+		},
+		{
+			aliases:     []string{"teams", "access"},
+			description: "list the workspace team accesses",
+			run:         c.workspacePage(TeamsPageName),
+		},
+		{
+			aliases:     []string{"states", "state"},
+			description: "list the workspace state versions",
+			run:         c.workspacePage(StateVersionsPageName),
+		},
+		{
+			aliases:     []string{"profiles", "profile", "ctx"},
+			description: "list profiles or switch to the named profile",
+			run:         c.runProfiles,
+			complete:    c.completeProfiles,
+		},
+		{
+			aliases:     []string{"help", "?"},
+			description: "show help",
+			run:         c.runHelp,
+		},
+		{
+			aliases:     []string{"q", "quit", "exit"},
+			description: "quit",
+			run:         c.runQuit,
+		},
+	}
+}
+
+// Open shows the prompt in place of the footer and focus it.
+func (c *CommandPrompt) Open() {
+	c.SetText("")
+	c.Autocomplete()
+	c.app.layout.RemoveItem(c.app.footer)
+	c.app.layout.AddItem(c, 2, 0, 1, 1, 0, 0, true)
+	c.app.SetFocus(c)
+
+	c.loadWorkspaces()
+}
+
+// Close restores the footer and gives the focus back to the current page.
+func (c *CommandPrompt) Close() {
+	c.app.layout.RemoveItem(c)
+	c.app.layout.AddItem(c.app.footer, 2, 0, 1, 1, 0, 0, false)
+
+	if c.app.currentPage != nil {
+		c.app.SetFocus(c.app.currentPage)
+	} else {
+		c.app.SetFocus(c.app.pages)
+	}
+}
+
+// Exec runs the command line, e.g. "ws app-prod".
+func (c *CommandPrompt) Exec(line string) error {
+	name, args := parseCommand(line)
+	if name == "" {
+		return nil
+	}
+
+	cmd := c.find(name)
+	if cmd == nil {
+		return fmt.Errorf("unknown command %s", name)
+	}
+	return cmd.run(args)
+}
+
+// keyboard runs the command on enter, even if the completion list is open,
+// which only takes the selected entry on tab.
+func (c *CommandPrompt) keyboard(evt *tcell.EventKey) *tcell.EventKey {
+	if evt.Key() != tcell.KeyEnter {
+		return evt
+	}
+
+	line := c.GetText()
+	c.Close()
+
+	if err := c.Exec(line); err != nil {
+		c.app.footer.ShowError(fmt.Sprintf("😵 %s", err.Error()))
+	}
+	return nil
+}
+
+func (c *CommandPrompt) done(key tcell.Key) {
+	if key == tcell.KeyEscape {
+		c.Close()
+	}
+}
+
+func (c *CommandPrompt) find(name string) *command {
+	for _, cmd := range c.commands {
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// autocomplete suggests the command aliases while typing the command and
+// the command arguments after the first space.
+func (c *CommandPrompt) autocomplete(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	entries := []string{}
+	name, arg, found := strings.Cut(text, " ")
+	if !found {
+		for _, cmd := range c.commands {
+			entries = append(entries, filterPrefix(cmd.aliases, name)...)
+		}
+		sort.Strings(entries)
+	} else if cmd := c.find(name); cmd != nil && cmd.complete != nil {
+		for _, e := range cmd.complete(arg) {
+			entries = append(entries, fmt.Sprintf("%s %s", name, e))
+		}
+	}
+
+	// nothing left to complete
+	if len(entries) == 0 || (len(entries) == 1 && entries[0] == text) {
+		return nil
+	}
+	return entries
+}
+
+// loadWorkspaces caches the first page of workspaces of the current
+// organization, used by the completion of `:ws`.
+func (c *CommandPrompt) loadWorkspaces() {
+	org := c.app.config.Organization
+	if org == "" || c.app.clientErr != nil {
+		c.workspaces = nil
+		return
+	}
+
+	tfeClient := c.app.tfeClient
+	go func() {
+		workspaces, err := tfeClient.ListWorkspaces(context.Background(), org, "", -1)
+		if err != nil {
+			return
+		}
+
+		names := []string{}
+		for _, w := range workspaces.Items {
+			names = append(names, w.Name)
+		}
+		sort.Strings(names)
+
+		c.app.QueueUpdateDraw(func() {
+			c.workspaces = names
+			if c.HasFocus() {
+				c.Autocomplete()
+			}
+		})
+	}()
+}
+
+func (c *CommandPrompt) completeWorkspaces(arg string) []string {
+	return filterPrefix(c.workspaces, arg)
+}
+
+func (c *CommandPrompt) completeProfiles(arg string) []string {
+	names := []string{}
+	for _, p := range c.app.config.Profiles {
+		names = append(names, p.Name)
+	}
+	return filterPrefix(names, arg)
+}
+
+func (c *CommandPrompt) runOrgs(args []string) error {
+	c.app.listOrgs(nil)
+	return nil
+}
+
+func (c *CommandPrompt) runWorkspaces(args []string) error {
+	if c.app.config.Organization == "" {
+		return errors.New("no organization selected")
+	}
+
+	if len(args) == 0 {
+		c.app.config.Workspace = ""
+		c.app.config.Save()
+		c.app.activatePage(WorkspacesPageName, nil, false)
+		return nil
+	}
+
+	c.app.config.Workspace = args[0]
+	c.app.config.Save()
+	c.app.activatePage(WorkspacePageName, nil, false)
+	return nil
+}
+
+func (c *CommandPrompt) runRun(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: run <run-id>")
+	}
+
+	if !c.app.clientAvailable(RunPageName) {
+		return nil
+	}
+
+	// the run may be of any workspace, the pages opened from the run page
+	// need its workspace selected
+	runID := args[0]
+	ctx := c.app.pageCtx
+	tfeClient := c.app.tfeClient
+	c.app.ShowLoading()
+	go func() {
+		run, err := tfeClient.ReadWorkspaceRun(ctx, runID)
+
+		c.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				c.app.footer.ShowError(fmt.Sprintf("😵 error reading the run %s: %s", runID, err))
+				return
+			}
+			if run.Workspace == nil || run.Workspace.Organization == nil {
+				c.app.footer.ShowError(fmt.Sprintf("😵 the workspace of the run %s is unknown", runID))
+				return
+			}
+
+			c.app.config.Organization = run.Workspace.Organization.Name
+			c.app.config.Workspace = run.Workspace.Name
+			c.app.config.RunID = run.ID
+			c.app.config.Save()
+			c.app.activatePage(RunPageName, nil, false)
+		})
+	}()
+	return nil
+}
+
+func (c *CommandPrompt) workspacePage(name string) func(args []string) error {
+	return func(args []string) error {
+		if c.app.config.Organization == "" || c.app.config.Workspace == "" {
+			return errors.New("no workspace selected")
+		}
+
+		c.app.activatePage(name, nil, false)
+		return nil
+	}
+}
+
+func (c *CommandPrompt) runProfiles(args []string) error {
+	if len(args) == 0 {
+		c.app.listProfiles(nil)
+		return nil
+	}
+
+	if c.app.config.FindProfile(args[0]) == nil {
+		return fmt.Errorf("profile %s not found", args[0])
+	}
+	c.app.switchProfile(args[0])
+	return nil
+}
+
+func (c *CommandPrompt) runHelp(args []string) error {
+	c.app.showHelp(nil)
+	return nil
+}
+
+func (c *CommandPrompt) runQuit(args []string) error {
+	c.app.quit(nil)
+	return nil
+}
+
+// parseCommand splits the command line into the command name and its
+// arguments, ignoring a leading colon.
+func parseCommand(line string) (string, []string) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], fields[1:]
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	found := []string{}
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			found = append(found, v)
+		}
+	}
+	return found
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestParseCommand(t *testing.T) {
+	testCases := []struct {
+		line         string
+		expectedName string
+		expectedArgs []string
+	}{
+		{line: "", expectedName: "", expectedArgs: nil},
+		{line: "  ", expectedName: "", expectedArgs: nil},
+		{line: "ws", expectedName: "ws", expectedArgs: []string{}},
+		{line: ":ws", expectedName: "ws", expectedArgs: []string{}},
+		{line: " ws  app-prod ", expectedName: "ws", expectedArgs: []string{"app-prod"}},
+		{line: "run run-123", expectedName: "run", expectedArgs: []string{"run-123"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			name, args := parseCommand(tc.line)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestCommandPromptAutocomplete(t *testing.T) {
+	a := NewAppWithClient(&config.Config{
+		Profiles: []*config.Profile{{Name: "work"}, {Name: "personal"}},
+	}, newFakeClient())
+	a.command.workspaces = []string{"app-dev", "app-prod"}
+
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "", expected: nil},
+		{text: "w", expected: []string{"workspace", "workspaces", "ws"}},
+		{text: "ws app-p", expected: []string{"ws app-prod"}},
+		{text: "ws app", expected: []string{"ws app-dev", "ws app-prod"}},
+		{text: "ws app-prod", expected: nil},
+		{text: "profile w", expected: []string{"profile work"}},
+		{text: "vars x", expected: nil},
+		{text: "foo x", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, a.command.autocomplete(tc.text))
+		})
+	}
+}
+
+func TestCommandWorkspace(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("ws app-prod")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("<app-prod>")
+	h.waitFor("Resource Count: 12")
+	assert.Equal(t, "app-prod", h.config.Workspace)
+}
+
+func TestCommandTabCompletion(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("ws app-d")
+	h.waitUntil(func() bool {
+		_, ok := h.find("ws app-dev")
+		return ok
+	}, "completion not shown")
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("<app-dev>")
+}
+
+func TestCommandRun(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("run run-new")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("<run-new>")
+	h.waitFor("Error: creating bucket")
+}
+
+func TestCommandRunSelectsWorkspace(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-plan", "add queue", tfe.RunPlanned))
+
+	h := newHarness(t, &config.Config{Organization: "acme"}, c)
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("run run-missing")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("error reading the run run-missing")
+
+	h.pressRune(':')
+	h.typeText("run run-plan")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<acme>   <app-prod>   <runs>   <run-plan>")
+	h.waitFor("run details")
+	h.onUI(func() {
+		assert.Equal(t, "app-prod", h.config.Workspace)
+	})
+
+	h.pressRune('a')
+	h.waitFor("Apply the run run-plan of the workspace app-prod?")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("ERROR: 😵 type app-prod to confirm")
+}
+
+func TestCommandVariablesAndTeams(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, newFakeClient())
+	h.waitFor("Resource Count: 12")
+
+	h.pressRune(':')
+	h.typeText("vars")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<variables>")
+	h.waitFor("eu-west-1")
+	h.waitFor("db_password")
+	assert.NotContains(t, h.text(), "hunter2")
+
+	h.pressRune(':')
+	h.typeText("teams")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<teams>")
+	h.waitFor("owners")
+}
+
+func TestCommandErrors(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("foo")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("unknown command foo")
+
+	h.pressRune(':')
+	h.typeText("vars")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("no workspace selected")
+}
+
+func TestCommandEscape(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune(':')
+	h.typeText("orgs")
+	h.pressKey(tcell.KeyEsc)
+	h.waitForGone(":orgs")
+	h.waitFor("app-dev")
+
+	assert.Equal(t, "acme", h.config.Organization)
+}
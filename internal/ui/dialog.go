@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const confirmDialogName string = "confirm"
+
+const confirmDialogWidth = 70
+
+// ConfirmDialog asks to confirm an action, shown over the current page. The
+// destructive actions require typing a text, like the workspace name, to be
+// confirmed, and other actions can ask for an optional text, like a reason.
+type ConfirmDialog struct {
+	*tview.Flex
+
+	app       *App
+	form      *tview.Form
+	input     *tview.InputField
+	expected  string
+	confirmed func(input string)
+}
+
+// NewConfirmDialog builds a dialog with an input field if the label is set,
+// where the expected text must be typed if it is set.
+func NewConfirmDialog(app *App, title, text, label, expected string, confirmed func(input string)) *ConfirmDialog {
+	d := &ConfirmDialog{
+		app:       app,
+		form:      tview.NewForm(),
+		expected:  expected,
+		confirmed: confirmed,
+	}
+
+	message := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true).
+		SetText(text)
+
+	if label != "" {
+		d.input = tview.NewInputField().SetLabel(label)
+		d.form.AddFormItem(d.input)
+	}
+	d.form.AddButton("Confirm", d.confirm)
+	d.form.AddButton("Cancel", d.app.closeDialog)
+	d.form.SetCancelFunc(d.app.closeDialog)
+	d.form.SetButtonsAlign(tview.AlignCenter)
+
+	height := strings.Count(text, "\n") + 1
+	formHeight := 3
+	if label != "" {
+		formHeight += 2
+	}
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(message, height, 0, false).
+		AddItem(d.form, formHeight, 0, true)
+	content.SetBorder(true)
+	content.SetBorderPadding(1, 0, 1, 1)
+	content.SetBorderColor(tcell.ColorOrangeRed)
+	content.SetTitle(fmt.Sprintf(" %s ", title))
+
+	d.Flex = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, height+formHeight+3, 0, true).
+			AddItem(nil, 0, 1, false), confirmDialogWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	return d
+}
+
+func (d *ConfirmDialog) confirm() {
+	input := ""
+	if d.input != nil {
+		input = d.input.GetText()
+	}
+	if d.expected != "" && input != d.expected {
+		d.app.footer.ShowError(fmt.Sprintf("😵 type %s to confirm", d.expected))
+		return
+	}
+
+	d.app.closeDialog()
+	d.confirmed(input)
+}
+
+// confirm shows a dialog over the current page to confirm an action, the
+// page keys are disabled while it is open.
+func (a *App) confirm(title, text, expected string, confirmed func()) {
+	label := ""
+	if expected != "" {
+		label = fmt.Sprintf("type %s to confirm", tview.Escape(expected))
+	}
+
+	a.showDialog(NewConfirmDialog(a, title, text, label, expected, func(string) {
+		confirmed()
+	}))
+}
+
+// prompt shows a dialog over the current page to confirm an action asking for
+// an optional text, e.g. the reason of the action.
+func (a *App) prompt(title, text, label string, confirmed func(input string)) {
+	a.showDialog(NewConfirmDialog(a, title, text, label, "", confirmed))
+}
+
+func (a *App) showDialog(d *ConfirmDialog) {
+	a.dialog = d
+	a.pages.AddPage(confirmDialogName, a.dialog, true, true)
+	a.SetFocus(a.dialog)
+}
+
+func (a *App) closeDialog() {
+	if a.dialog == nil {
+		return
+	}
+
+	a.dialog = nil
+	a.pages.RemovePage(confirmDialogName)
+	if a.currentPage != nil {
+		a.SetFocus(a.currentPage)
+	}
+}
@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// unknownValue replaces the values only known after the apply, like
+// terraform does.
+const unknownValue = "(known after apply)"
+
+const (
+	diffKeyValFmt = "%s[sym::b]%s[-::-] [key::b]%s[colon::-]: [val::]%s"
+	diffValFmt    = "%s[sym::b]%s[-::-] [val::]%s"
+	diffCloseFmt  = "%s  [val::]%s"
+)
+
+// diffSymbols are the colors of the diff symbols, the same as the plan
+// change actions.
+var diffSymbols = map[string]string{
+	"+": "green",
+	"-": "red",
+	"~": "yellow",
+	" ": "foreground",
+}
+
+// diffSide is a value on one side of the diff, with its sensitive and unknown
+// markers, which are true or have the shape of the value.
+type diffSide struct {
+	value     interface{}
+	sensitive interface{}
+	unknown   interface{}
+}
+
+func (s diffSide) isUnknown() bool {
+	unknown, _ := s.unknown.(bool)
+	return unknown
+}
+
+func (s diffSide) isSensitive() bool {
+	sensitive, _ := s.sensitive.(bool)
+	return sensitive
+}
+
+// exists is false for the null values, which terraform shows as absent.
+func (s diffSide) exists() bool {
+	return s.value != nil || s.isUnknown()
+}
+
+// child returns the side of a map key or list index.
+func (s diffSide) child(key interface{}) diffSide {
+	return diffSide{
+		value:     childValue(s.value, key),
+		sensitive: childValue(s.sensitive, key),
+		unknown:   childValue(s.unknown, key),
+	}
+}
+
+func childValue(value interface{}, key interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			return v[k]
+		}
+	case []interface{}:
+		if i, ok := key.(int); ok && i < len(v) {
+			return v[i]
+		}
+	}
+	return nil
+}
+
+// diffLine is a line of the diff, where the value of the nested values is
+// the opening bracket and the closing bracket lines have no symbol.
+type diffLine struct {
+	depth  int
+	symbol string
+	key    string
+	value  string
+	open   bool
+	close  bool
+}
+
+func (l diffLine) indent() string {
+	return strings.Repeat("    ", l.depth)
+}
+
+// String returns the line without colors.
+func (l diffLine) String() string {
+	switch {
+	case l.close:
+		return fmt.Sprintf("%s  %s", l.indent(), l.value)
+	case l.key == "":
+		return fmt.Sprintf("%s%s %s", l.indent(), l.symbol, l.value)
+	}
+	return fmt.Sprintf("%s%s %s: %s", l.indent(), l.symbol, l.key, l.value)
+}
+
+// colorize returns the line with color tags, styled like colorizeYAML.
+func (l diffLine) colorize() string {
+	value := tview.Escape(l.value)
+	value = strings.ReplaceAll(value, unknownValue, fmt.Sprintf("[gray::i]%s[val::-]", unknownValue))
+	value = strings.ReplaceAll(value, sensitiveValue, fmt.Sprintf("[gray::i]%s[val::-]", sensitiveValue))
+	value = strings.ReplaceAll(value, " -> ", " [blue]->[val] ")
+
+	var line string
+	switch {
+	case l.close:
+		line = fmt.Sprintf(diffCloseFmt, l.indent(), value)
+	case l.key == "":
+		line = fmt.Sprintf(diffValFmt, l.indent(), l.symbol, value)
+	default:
+		line = fmt.Sprintf(diffKeyValFmt, l.indent(), l.symbol, tview.Escape(l.key), value)
+	}
+
+	line = strings.Replace(line, "[sym", "["+diffSymbols[l.symbol], 1)
+	line = strings.Replace(line, "[key", "[magenta", 1)
+	line = strings.Replace(line, "[colon", "[blue", 1)
+	return strings.ReplaceAll(line, "[val", "[foreground")
+}
+
+// diffChange returns the lines of the nested diff of the change values, with
+// the sensitive values masked and the unknown values shown as known after
+// apply.
+func diffChange(before, after diffSide) []diffLine {
+	lines, _ := diffChildren(0, before, after)
+	return lines
+}
+
+// changedLines returns the lines of the changed attributes only, without the
+// unchanged nested values.
+func changedLines(lines []diffLine) []diffLine {
+	changed := []diffLine{}
+	skipDepth := -1
+	for _, l := range lines {
+		if skipDepth >= 0 {
+			if l.close && l.depth == skipDepth {
+				skipDepth = -1
+			}
+			continue
+		}
+
+		if l.symbol == " " {
+			if l.open {
+				skipDepth = l.depth
+			}
+			continue
+		}
+		changed = append(changed, l)
+	}
+	return changed
+}
+
+// diffAttribute returns the lines of the diff of an attribute and whether it
+// changed.
+func diffAttribute(depth int, key string, before, after diffSide) ([]diffLine, bool) {
+	line := diffLine{depth: depth, key: key}
+
+	switch {
+	case !before.exists() && !after.exists():
+		return nil, false
+
+	case before.isSensitive() || after.isSensitive():
+		line.symbol, line.value = " ", sensitiveValue
+		changed := after.isUnknown() || !reflect.DeepEqual(before.value, after.value)
+		switch {
+		case !before.exists():
+			line.symbol = "+"
+		case !after.exists():
+			line.symbol = "-"
+		case changed:
+			line.symbol = "~"
+		}
+		return []diffLine{line}, changed
+
+	case after.isUnknown():
+		line.symbol = "~"
+		line.value = unknownValue
+		if !before.exists() {
+			line.symbol = "+"
+		} else if scalar, ok := fmtDiffScalar(before); ok {
+			line.value = fmt.Sprintf("%s -> %s", scalar, unknownValue)
+		}
+		return []diffLine{line}, true
+	}
+
+	if open, closing, ok := diffBrackets(before.value, after.value); ok {
+		children, changed := diffChildren(depth+1, before, after)
+
+		line.symbol, line.value, line.open = " ", open, true
+		switch {
+		case !before.exists():
+			line.symbol = "+"
+		case !after.exists():
+			line.symbol = "-"
+		case changed:
+			line.symbol = "~"
+		}
+
+		lines := append([]diffLine{line}, children...)
+		lines = append(lines, diffLine{depth: depth, value: closing, close: true})
+		return lines, changed
+	}
+
+	beforeText, _ := fmtDiffScalar(before)
+	afterText, _ := fmtDiffScalar(after)
+	switch {
+	case !before.exists():
+		line.symbol, line.value = "+", afterText
+	case !after.exists():
+		line.symbol, line.value = "-", fmt.Sprintf("%s -> null", beforeText)
+	case reflect.DeepEqual(before.value, after.value):
+		line.symbol, line.value = " ", afterText
+	default:
+		line.symbol, line.value = "~", fmt.Sprintf("%s -> %s", beforeText, afterText)
+	}
+	return []diffLine{line}, line.symbol != " "
+}
+
+// diffChildren returns the diff of the map keys, sorted, or of the list
+// items, and whether any of them changed.
+func diffChildren(depth int, before, after diffSide) ([]diffLine, bool) {
+	lines := []diffLine{}
+	changed := false
+
+	add := func(key string, childKey interface{}) {
+		l, c := diffAttribute(depth, key, before.child(childKey), after.child(childKey))
+		lines = append(lines, l...)
+		changed = changed || c
+	}
+
+	if isDiffList(before.value) || isDiffList(after.value) {
+		size := len(asDiffList(before.value))
+		if n := len(asDiffList(after.value)); n > size {
+			size = n
+		}
+		for i := 0; i < size; i++ {
+			add("", i)
+		}
+		return lines, changed
+	}
+
+	keys := map[string]bool{}
+	for _, v := range []interface{}{before.value, after.value, after.unknown} {
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				keys[k] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		add(k, k)
+	}
+	return lines, changed
+}
+
+// diffBrackets returns the brackets of the nested values, if both values are
+// maps or both lists, or one of them is null.
+func diffBrackets(before, after interface{}) (string, string, bool) {
+	_, beforeMap := before.(map[string]interface{})
+	_, afterMap := after.(map[string]interface{})
+	if (beforeMap || before == nil) && (afterMap || after == nil) && (beforeMap || afterMap) {
+		return "{", "}", true
+	}
+
+	if (isDiffList(before) || before == nil) && (isDiffList(after) || after == nil) && (isDiffList(before) || isDiffList(after)) {
+		return "[", "]", true
+	}
+	return "", "", false
+}
+
+func isDiffList(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
+
+func asDiffList(value interface{}) []interface{} {
+	l, _ := value.([]interface{})
+	return l
+}
+
+// fmtDiffScalar returns the value as JSON, with the sensitive and unknown
+// nested values masked, and whether it is a scalar value.
+func fmtDiffScalar(s diffSide) (string, bool) {
+	switch v := s.value.(type) {
+	case nil:
+		return "null", true
+	case string:
+		return strconv.Quote(v), true
+	case map[string]interface{}, []interface{}:
+		content, _ := json.Marshal(maskSensitive(v, s.sensitive))
+		return string(content), false
+	}
+
+	content, _ := json.Marshal(s.value)
+	return string(content), true
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestDiffChange(t *testing.T) {
+	testCases := []struct {
+		name     string
+		before   diffSide
+		after    diffSide
+		expected []string
+	}{
+		{
+			name:   "create",
+			before: diffSide{},
+			after: diffSide{
+				value:   map[string]interface{}{"bucket": "acme-logs", "tags": map[string]interface{}{"team": "platform"}},
+				unknown: map[string]interface{}{"arn": true, "tags": map[string]interface{}{}},
+			},
+			expected: []string{
+				"+ arn: (known after apply)",
+				`+ bucket: "acme-logs"`,
+				"+ tags: {",
+				`    + team: "platform"`,
+				"  }",
+			},
+		},
+		{
+			name: "update",
+			before: diffSide{value: map[string]interface{}{
+				"count":   float64(1),
+				"enabled": true,
+				"name":    "web",
+				"old":     "value",
+				"tags":    map[string]interface{}{"env": "dev", "team": "platform"},
+			}},
+			after: diffSide{value: map[string]interface{}{
+				"count":   float64(2),
+				"enabled": true,
+				"name":    "web",
+				"tags":    map[string]interface{}{"env": "prod", "team": "platform"},
+			}},
+			expected: []string{
+				"~ count: 1 -> 2",
+				"  enabled: true",
+				`  name: "web"`,
+				`- old: "value" -> null`,
+				"~ tags: {",
+				`    ~ env: "dev" -> "prod"`,
+				`      team: "platform"`,
+				"  }",
+			},
+		},
+		{
+			name:   "delete",
+			before: diffSide{value: map[string]interface{}{"name": "web"}},
+			after:  diffSide{},
+			expected: []string{
+				`- name: "web" -> null`,
+			},
+		},
+		{
+			name:   "known after apply",
+			before: diffSide{value: map[string]interface{}{"id": "i-123", "ip": "10.0.0.1"}},
+			after: diffSide{
+				value:   map[string]interface{}{"ip": "10.0.0.1"},
+				unknown: map[string]interface{}{"id": true},
+			},
+			expected: []string{
+				`~ id: "i-123" -> (known after apply)`,
+				`  ip: "10.0.0.1"`,
+			},
+		},
+		{
+			name: "sensitive",
+			before: diffSide{
+				value:     map[string]interface{}{"password": "hunter2", "token": "abc", "user": map[string]interface{}{"name": "admin", "key": "k1"}},
+				sensitive: map[string]interface{}{"password": true, "token": true, "user": map[string]interface{}{"key": true}},
+			},
+			after: diffSide{
+				value:     map[string]interface{}{"password": "hunter3", "token": "abc", "user": map[string]interface{}{"name": "root", "key": "k2"}},
+				sensitive: map[string]interface{}{"password": true, "token": true, "user": map[string]interface{}{"key": true}},
+			},
+			expected: []string{
+				"~ password: (sensitive value)",
+				"  token: (sensitive value)",
+				"~ user: {",
+				"    ~ key: (sensitive value)",
+				`    ~ name: "admin" -> "root"`,
+				"  }",
+			},
+		},
+		{
+			name: "sensitive known after apply",
+			before: diffSide{
+				value:     map[string]interface{}{"password": "hunter2"},
+				sensitive: map[string]interface{}{"password": true},
+			},
+			after: diffSide{
+				value:   map[string]interface{}{},
+				unknown: map[string]interface{}{"password": true},
+			},
+			expected: []string{
+				"~ password: (sensitive value)",
+			},
+		},
+		{
+			name:   "lists",
+			before: diffSide{value: map[string]interface{}{"ports": []interface{}{float64(80), float64(443)}}},
+			after:  diffSide{value: map[string]interface{}{"ports": []interface{}{float64(8080), float64(443), float64(22)}}},
+			expected: []string{
+				"~ ports: [",
+				"    ~ 80 -> 8080",
+				"      443",
+				"    + 22",
+				"  ]",
+			},
+		},
+		{
+			name:   "nested value replaced by a scalar",
+			before: diffSide{value: map[string]interface{}{"policy": map[string]interface{}{"a": "b"}}},
+			after:  diffSide{value: map[string]interface{}{"policy": "none"}},
+			expected: []string{
+				`~ policy: {"a":"b"} -> "none"`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []string{}
+			for _, l := range diffChange(tc.before, tc.after) {
+				lines = append(lines, l.String())
+			}
+			assert.Equal(t, tc.expected, lines)
+		})
+	}
+}
+
+func TestDiffLineColorize(t *testing.T) {
+	testCases := []struct {
+		line     diffLine
+		expected string
+	}{
+		{
+			line:     diffLine{symbol: "~", key: "id", value: `"i-123" -> (known after apply)`},
+			expected: `[yellow::b]~[-::-] [magenta::b]id[blue::-]: [foreground::]"i-123" [blue]->[foreground] [gray::i](known after apply)[foreground::-]`,
+		},
+		{
+			line:     diffLine{depth: 1, symbol: "+", key: "tags[0]", value: "(sensitive value)"},
+			expected: `    [green::b]+[-::-] [magenta::b]tags[0[][blue::-]: [foreground::][gray::i](sensitive value)[foreground::-]`,
+		},
+		{
+			line:     diffLine{symbol: "-", value: `"[x]"`},
+			expected: `[red::b]-[-::-] [foreground::]"[x[]"`,
+		},
+		{
+			line:     diffLine{value: "}", close: true},
+			expected: `  [foreground::]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.line.colorize())
+	}
+}
+
+func TestPlanChangePageDiff(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanJSON("plan-run-new", newPlanJSON())
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod", RunID: "run-new"}, c)
+	h.waitFor("workspace details")
+	h.app.QueueUpdate(func() {
+		h.app.activatePage(PlanChangesPageName, nil, false)
+	})
+	h.waitFor("aws_s3_bucket.logs")
+
+	h.pressRune('/')
+	h.typeText("db")
+	h.pressKey(tcell.KeyEnter)
+	h.waitForGone("aws_s3_bucket.logs")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor(`  engine: "postgres"`)
+	h.waitFor("~ password: (sensitive value)")
+	fg, _, _ := h.styleOf("~ password").Decompose()
+	assert.Equal(t, tcell.ColorYellow, fg)
+	assert.NotContains(t, h.text(), "hunter")
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editorCommand returns the editor of the user, vi if none is set.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(env)); len(editor) > 0 {
+			return editor
+		}
+	}
+	return []string{"vi"}
+}
+
+// editText opens the text in the editor of the user, suspending the app
+// while the editor runs, and returns the edited text. The tests replace it to
+// avoid running an editor.
+var editText = func(a *App, text, extension string) (string, error) {
+	f, err := os.CreateTemp("", fmt.Sprintf("terrui-*%s", extension))
+	if err != nil {
+		return "", fmt.Errorf("error creating the file to edit: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", fmt.Errorf("error writing the file to edit: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("error writing the file to edit: %w", err)
+	}
+
+	editor := editorCommand()
+	var runErr error
+	a.Suspend(func() {
+		cmd := exec.Command(editor[0], append(editor[1:], f.Name())...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		runErr = cmd.Run()
+	})
+	if runErr != nil {
+		return "", fmt.Errorf("error running %s: %w", editor[0], runErr)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("error reading the edited file: %w", err)
+	}
+
+	// editors add a final new line
+	return strings.TrimSuffix(string(edited), "\n"), nil
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const EffectiveVariablesPageName string = "effective variables"
+
+// EffectiveVariablesPageSource lists the variables the runs of the workspace
+// receive, merging the workspace variables with the variable sets applied to
+// it. The overridden variables are dimmed, with the variable overriding them.
+type EffectiveVariablesPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	variables []*client.EffectiveVariable
+}
+
+func NewEffectiveVariablesPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &EffectiveVariablesPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (e *EffectiveVariablesPageSource) SupportsSearch() bool {
+	return false
+}
+
+// Search reads the workspace variables, all pages, and the variable sets
+// to resolve them, so the list has a single page.
+func (e *EffectiveVariablesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	workspace, err := e.tfeClient.ReadWorkspace(ctx, e.app.config.Organization, e.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+
+	variables, err := allWorkspaceVariables(ctx, e.tfeClient, workspace.ID)
+	if err != nil {
+		return err
+	}
+
+	sets, err := e.tfeClient.ListWorkspaceVariableSets(ctx, workspace.ID)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace variable sets: %w", err)
+	}
+
+	e.variables = client.ResolveVariables(variables, sets)
+	return nil
+}
+
+func (e *EffectiveVariablesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "KEY"},
+		{Name: "VALUE"},
+		{Name: "CATEGORY"},
+		{Name: "SOURCE"},
+		{Name: "STATUS"},
+	}
+}
+
+func (e *EffectiveVariablesPageSource) RenderRows(table *tview.Table) {
+	for i, v := range e.variables {
+		r := i + 1
+
+		value := v.Value
+		if v.Sensitive {
+			value = "******"
+		}
+
+		status := "applied"
+		var attributes tcell.AttrMask
+		if v.OverriddenBy != nil {
+			status = fmt.Sprintf("overridden by %s", v.OverriddenBy.Source())
+			attributes = tcell.AttrDim
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(v.Key).SetExpansion(1).SetAttributes(attributes))
+		table.SetCell(r, 1, tview.NewTableCell(value).SetExpansion(2).SetAttributes(attributes))
+		table.SetCell(r, 2, tview.NewTableCell(string(v.Category)).SetExpansion(1).SetAttributes(attributes))
+		table.SetCell(r, 3, tview.NewTableCell(v.Source()).SetExpansion(2).SetAttributes(attributes))
+		table.SetCell(r, 4, tview.NewTableCell(status).SetExpansion(2).SetAttributes(attributes))
+	}
+}
+
+func (e *EffectiveVariablesPageSource) Crumb() []string {
+	return []string{
+		e.app.config.Organization,
+		e.app.config.Workspace,
+		EffectiveVariablesPageName,
+	}
+}
+
+func (e *EffectiveVariablesPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		return nil
+	}
+}
+
+func (e *EffectiveVariablesPageSource) Name() string {
+	return "variable"
+}
+
+func (e *EffectiveVariablesPageSource) NameList() string {
+	return EffectiveVariablesPageName
+}
+
+func (e *EffectiveVariablesPageSource) Empty() bool {
+	return len(e.variables) == 0
+}
+
+func (e *EffectiveVariablesPageSource) CurrentPage() int {
+	return 1
+}
+
+func (e *EffectiveVariablesPageSource) TotalCount() int {
+	return len(e.variables)
+}
+
+func (e *EffectiveVariablesPageSource) TotalPages() int {
+	return 1
+}
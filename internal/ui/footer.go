@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -15,7 +16,12 @@ type Footer struct {
 	errorColor   tcell.Color
 	errorTimeout int
 	previousText string
+	showing      bool
 	titleColor   tcell.Color
+
+	// calls numbers the Show calls, shown is the number of the call shown.
+	calls int64
+	shown int64
 }
 
 func NewFooter(app *App, initialText string, color tcell.Color, errorTimeout int) *Footer {
@@ -36,9 +42,13 @@ func NewFooter(app *App, initialText string, color tcell.Color, errorTimeout int
 	return &f
 }
 
+// ShowText shows the text, or keeps it to show after the text shown by Show.
 func (f *Footer) ShowText(text string) {
 	go f.app.QueueUpdateDraw(func() {
 		f.previousText = text
+		if f.showing {
+			return
+		}
 		f.SetTextColor(f.titleColor)
 		f.SetText(text)
 	})
@@ -48,13 +58,30 @@ func (f *Footer) ShowError(text string) {
 	f.Show(fmt.Sprintf("ERROR: %s", text), f.errorColor)
 }
 
+// Show shows the text until the error timeout, unless another text is shown
+// before, and then the previous text again. The updates are queued from
+// goroutines, so the text of an earlier call queued last is not shown.
 func (f *Footer) Show(text string, color tcell.Color) {
+	call := atomic.AddInt64(&f.calls, 1)
 	go f.app.QueueUpdateDraw(func() {
+		if call < f.shown {
+			return
+		}
+		f.shown = call
+		f.showing = true
+		shown := f.shown
 		f.SetTextColor(color)
 		f.SetText(text)
+
+		go func() {
+			time.Sleep(time.Duration(f.errorTimeout) * time.Second)
+			f.app.QueueUpdateDraw(func() {
+				if f.shown == shown {
+					f.showing = false
+					f.SetTextColor(f.titleColor)
+					f.SetText(f.previousText)
+				}
+			})
+		}()
 	})
-	go func() {
-		time.Sleep(time.Duration(f.errorTimeout) * time.Second)
-		f.ShowText(f.previousText)
-	}()
 }
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/require"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+const harnessTimeout = 3 * time.Second
+
+// harness drives the App on a simulation screen using the fake TFE client.
+type harness struct {
+	t      *testing.T
+	app    *App
+	screen tcell.SimulationScreen
+	client *fake.Client
+	config *config.Config
+	// stopped is closed once the app stops running.
+	stopped chan struct{}
+}
+
+func newHarness(t *testing.T, cfg *config.Config, c *fake.Client) *harness {
+	t.Helper()
+
+	return startHarness(t, cfg, c, func() *App {
+		return NewAppWithClient(cfg, c)
+	})
+}
+
+// startHarness runs the App created by newApp.
+func startHarness(t *testing.T, cfg *config.Config, c *fake.Client, newApp func() *App) *harness {
+	t.Helper()
+
+	// the app saves the configuration when navigating
+	t.Setenv("HOME", t.TempDir())
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, screen.Init())
+	screen.SetSize(200, 50)
+
+	app := newApp()
+	app.SetScreen(screen)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		app.Run()
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-stopped:
+		default:
+			app.Stop()
+			<-stopped
+		}
+	})
+
+	return &harness{
+		t:       t,
+		app:     app,
+		screen:  screen,
+		client:  c,
+		config:  cfg,
+		stopped: stopped,
+	}
+}
+
+// pressKey injects a special key, e.g. tcell.KeyEnter. Keys are posted with
+// PostEventWait because InjectKey drops them once the event queue is full.
+func (h *harness) pressKey(key tcell.Key) {
+	h.screen.PostEventWait(tcell.NewEventKey(key, 0, tcell.ModNone))
+}
+
+// pressRune injects a printable key.
+func (h *harness) pressRune(r rune) {
+	h.screen.PostEventWait(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+}
+
+// typeText injects each rune of the text.
+func (h *harness) typeText(text string) {
+	for _, r := range text {
+		h.pressRune(r)
+	}
+}
+
+// screenLine is a rendered row with the screen cell of each rune.
+type screenLine struct {
+	runes []rune
+	cells []tcell.SimCell
+}
+
+// onUI runs f on the event loop goroutine and waits for it, to read the
+// state the app changes there, like the screen and the pages.
+func (h *harness) onUI(f func()) {
+	h.t.Helper()
+
+	done := make(chan struct{})
+	h.app.QueueUpdate(func() {
+		f()
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(harnessTimeout):
+		h.t.Fatal("the event loop is blocked")
+	}
+}
+
+// contents returns a copy of the screen cells.
+func (h *harness) contents() ([]tcell.SimCell, int, int) {
+	var cells []tcell.SimCell
+	var width, height int
+	h.onUI(func() {
+		var contents []tcell.SimCell
+		contents, width, height = h.screen.GetContents()
+		cells = append(cells, contents...)
+	})
+	return cells, width, height
+}
+
+// lines returns the rendered screen, one entry per row. Wide runes, like
+// emojis, take two cells on the screen but only one rune in the line.
+func (h *harness) lines() []screenLine {
+	cells, width, height := h.contents()
+
+	lines := make([]screenLine, height)
+	for y := 0; y < height; y++ {
+		l := screenLine{}
+		for x := 0; x < width; x++ {
+			c := cells[y*width+x]
+			r := ' '
+			if len(c.Runes) > 0 && c.Runes[0] != 0 {
+				r = c.Runes[0]
+			}
+			l.runes = append(l.runes, r)
+			l.cells = append(l.cells, c)
+
+			if runewidth.RuneWidth(r) == 2 {
+				x++
+			}
+		}
+		lines[y] = l
+	}
+	return lines
+}
+
+// text returns the whole rendered screen.
+func (h *harness) text() string {
+	b := strings.Builder{}
+	for _, l := range h.lines() {
+		b.WriteString(string(l.runes))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// find returns the cell where the text starts.
+func (h *harness) find(text string) (tcell.SimCell, bool) {
+	search := []rune(text)
+	for _, l := range h.lines() {
+		for x := 0; x+len(search) <= len(l.runes); x++ {
+			if string(l.runes[x:x+len(search)]) == text {
+				return l.cells[x], true
+			}
+		}
+	}
+	return tcell.SimCell{}, false
+}
+
+// waitFor waits until the text is rendered.
+func (h *harness) waitFor(text string) {
+	h.t.Helper()
+	h.waitUntil(func() bool {
+		return strings.Contains(h.text(), text)
+	}, "text %q not rendered", text)
+}
+
+// waitForGone waits until the text is no longer rendered.
+func (h *harness) waitForGone(text string) {
+	h.t.Helper()
+	h.waitUntil(func() bool {
+		return !strings.Contains(h.text(), text)
+	}, "text %q still rendered", text)
+}
+
+// waitUntil waits for the condition, failing the test with the rendered
+// screen if it does not happen in time.
+func (h *harness) waitUntil(condition func() bool, msg string, args ...interface{}) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(harnessTimeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	h.t.Fatalf(msg+"\n%s", append(args, h.text())...)
+}
+
+// styleOf returns the style of the first cell of the rendered text.
+func (h *harness) styleOf(text string) tcell.Style {
+	h.t.Helper()
+
+	c, ok := h.find(text)
+	require.True(h.t, ok, "text %q not rendered", text)
+	return c.Style
+}
+
+// newFakeClient returns a client with an organization, two workspaces and a
+// few runs, enough to navigate through all the pages.
+func newFakeClient() *fake.Client {
+	c := fake.NewClient()
+
+	c.AddOrganization(fake.NewOrganization("acme"))
+	c.AddOrganization(fake.NewOrganization("globex"))
+
+	prod := c.AddWorkspace("acme", fake.NewWorkspace("ws-prod", "app-prod"))
+	prod.TagNames = []string{"prod", "app"}
+	prod.ResourceCount = 12
+	dev := c.AddWorkspace("acme", fake.NewWorkspace("ws-dev", "app-dev"))
+
+	c.AddVariable(prod.ID, fake.NewVariable("var-1", "region", "eu-west-1"))
+	secret := c.AddVariable(prod.ID, fake.NewVariable("var-2", "db_password", "hunter2"))
+	secret.Sensitive = true
+
+	c.AddTeamAccess(prod.ID, fake.NewTeamAccess("owners", tfe.AccessAdmin))
+
+	c.AddRun(prod.ID, fake.NewRun("run-old", "first apply", tfe.RunApplied))
+	c.AddRun(prod.ID, fake.NewRun("run-new", "add bucket", tfe.RunErrored))
+	c.AddRun(dev.ID, fake.NewRun("run-dev", "init", tfe.RunApplied))
+	c.SetPlanLogs("plan-run-new", strings.Join([]string{
+		"Terraform v1.1.9",
+		`{"@level":"info","@message":"aws_s3_bucket.b: Plan to create","type":"planned_change"}`,
+		`{"@level":"info","@message":"Plan: 1 to add, 0 to change, 0 to destroy.","type":"change_summary"}`,
+	}, "\n"))
+	c.SetApplyLogs("apply-run-new", `{"@level":"error","@message":"Error: creating bucket","type":"diagnostic"}`)
+
+	return c
+}
@@ -52,8 +52,18 @@ func (h *Header) draw() {
 }
 
 func (h *Header) SetCrumb(crumbs []string) *Header {
-	h.crumb = append(h.baseCrumb, crumbs...)
+	h.crumb = append(append([]string{}, h.baseCrumb...), crumbs...)
 	h.draw()
 
 	return h
 }
+
+// SetProfile shows the active profile right after the logo.
+func (h *Header) SetProfile(profile string) *Header {
+	h.baseCrumb = []string{"terrUI"}
+	if profile != "" {
+		h.baseCrumb = append(h.baseCrumb, profile)
+	}
+
+	return h
+}
@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
 )
 
 const HelpPageName string = "help"
@@ -12,8 +17,7 @@ const HelpPageName string = "help"
 type HelpPage struct {
 	*tview.Table
 
-	app      *App
-	returnTo Page
+	app *App
 }
 
 type helpEntry struct {
@@ -21,17 +25,16 @@ type helpEntry struct {
 	description string
 }
 
-func NewHelpPage(app *App) Page {
+func NewHelpPage(app *App, tfeClient client.TFEClient) Page {
 	h := &HelpPage{
 		Table: tview.NewTable(),
 
-		app:      app,
-		returnTo: app.currentPage,
+		app: app,
 	}
 	return h
 }
 
-func (h *HelpPage) Load() error {
+func (h *HelpPage) Load(ctx context.Context) error {
 	return nil
 }
 
@@ -42,6 +45,9 @@ func (h *HelpPage) View() string {
 
 	helpEntries := []helpEntry{}
 	for k, a := range h.app.actions {
+		if !a.Visible {
+			continue
+		}
 		helpEntries = append(helpEntries, helpEntry{
 			action:      tcell.KeyNames[k],
 			description: a.Description,
@@ -49,6 +55,13 @@ func (h *HelpPage) View() string {
 	}
 	sort.Slice(helpEntries, func(i, j int) bool { return helpEntries[i].description < helpEntries[j].description })
 
+	for _, c := range h.app.command.commands {
+		helpEntries = append(helpEntries, helpEntry{
+			action:      fmt.Sprintf(":%s", strings.Join(c.aliases, " | :")),
+			description: c.description,
+		})
+	}
+
 	for i, e := range helpEntries {
 		r := i + 1
 		h.SetCell(r, 0, tview.NewTableCell(e.action).SetExpansion(1))
@@ -69,8 +82,7 @@ func (h *HelpPage) Crumb() []string {
 }
 
 func (h *HelpPage) exitHelp(ek *tcell.EventKey) *tcell.EventKey {
-	h.app.activatePage(h.app.currentPage.Name(), h.app.currentPage, true)
-	return nil
+	return h.app.goBack(ek)
 }
 
 func (h *HelpPage) Name() string {
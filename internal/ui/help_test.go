@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestHelpPage(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('?')
+	h.waitFor("<help>")
+	h.waitFor("list organizations")
+	h.waitFor("search workspaces")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitForGone("<help>")
+	h.waitFor("app-dev")
+}
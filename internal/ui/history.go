@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+const historySize int = 50
+
+// historyEntry is a visited page and the configuration it was shown with.
+// Returning to an entry shows the same page instance, keeping its selected
+// row, scroll position, search text and pagination.
+type historyEntry struct {
+	name string
+	page Page
+
+	// focus is the primitive focused when the page was left
+	focus tview.Primitive
+	// rendered is false if the page was left before being loaded
+	rendered bool
+
+	organization string
+	workspace    string
+	runID        string
+}
+
+func newHistoryEntry(name string, page Page, c *config.Config) *historyEntry {
+	return &historyEntry{
+		name:         name,
+		page:         page,
+		organization: c.Organization,
+		workspace:    c.Workspace,
+		runID:        c.RunID,
+	}
+}
+
+// restore sets the configuration back to the one the page was shown with.
+func (e *historyEntry) restore(c *config.Config) {
+	c.Organization = e.organization
+	c.Workspace = e.workspace
+	c.RunID = e.runID
+	c.Save()
+}
+
+// History is the back and forward navigation stack.
+type History struct {
+	back    []*historyEntry
+	forward []*historyEntry
+}
+
+// Push records the page being left when navigating to a new page, which
+// discards the forward history.
+func (h *History) Push(e *historyEntry) {
+	h.back = append(h.back, e)
+	if len(h.back) > historySize {
+		h.back = h.back[len(h.back)-historySize:]
+	}
+	h.forward = nil
+}
+
+// Back returns the previous page, or nil if there is none, moving the current
+// page to the forward history.
+func (h *History) Back(current *historyEntry) *historyEntry {
+	if len(h.back) == 0 {
+		return nil
+	}
+
+	e := h.back[len(h.back)-1]
+	h.back = h.back[:len(h.back)-1]
+	if current != nil {
+		h.forward = append(h.forward, current)
+	}
+	return e
+}
+
+// Forward returns the next page, or nil if there is none, moving the current
+// page to the back history.
+func (h *History) Forward(current *historyEntry) *historyEntry {
+	if len(h.forward) == 0 {
+		return nil
+	}
+
+	e := h.forward[len(h.forward)-1]
+	h.forward = h.forward[:len(h.forward)-1]
+	if current != nil {
+		h.back = append(h.back, current)
+	}
+	return e
+}
+
+// Clear removes all the entries, e.g. when switching profiles.
+func (h *History) Clear() {
+	h.back = nil
+	h.forward = nil
+}
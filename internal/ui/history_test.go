@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestHistory(t *testing.T) {
+	orgs := &historyEntry{name: OrganizationsPageName}
+	workspaces := &historyEntry{name: WorkspacesPageName}
+	workspace := &historyEntry{name: WorkspacePageName}
+
+	h := &History{}
+	assert.Nil(t, h.Back(orgs))
+	assert.Nil(t, h.Forward(orgs))
+
+	h.Push(orgs)
+	h.Push(workspaces)
+
+	assert.Equal(t, workspaces, h.Back(workspace))
+	assert.Equal(t, orgs, h.Back(workspaces))
+	assert.Nil(t, h.Back(orgs))
+
+	assert.Equal(t, workspaces, h.Forward(orgs))
+	assert.Equal(t, workspace, h.Forward(workspaces))
+	assert.Nil(t, h.Forward(workspace))
+
+	// navigating to a new page discards the forward history
+	h.Back(workspace)
+	h.Push(workspaces)
+	assert.Nil(t, h.Forward(workspace))
+}
+
+func TestHistorySize(t *testing.T) {
+	h := &History{}
+	for i := 0; i < historySize+10; i++ {
+		h.Push(&historyEntry{})
+	}
+	assert.Len(t, h.back, historySize)
+}
+
+func TestNavigationBackAndForward(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('/')
+	h.typeText("dev")
+	h.pressKey(tcell.KeyEnter)
+	h.waitForGone("app-prod")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-dev", h.config.Workspace)
+
+	// the search results are kept
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("🔎 dev")
+	h.waitForGone("workspace details")
+	assert.NotContains(t, h.text(), "app-prod")
+	assert.Equal(t, "", h.config.Workspace)
+
+	h.pressRune(']')
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-dev", h.config.Workspace)
+
+	h.pressRune('[')
+	h.waitFor("🔎 dev")
+	assert.Equal(t, "", h.config.Workspace)
+}
+
+func TestNavigationKeepsSelection(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-dev", h.config.Workspace)
+
+	h.pressKey(tcell.KeyBackspace2)
+	h.waitForGone("workspace details")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-dev", h.config.Workspace)
+}
+
+func TestNavigationGoesUpWithoutHistory(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newFakeClient())
+	h.waitFor("workspace details")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("app-dev")
+	h.waitFor("<workspaces>")
+	assert.Equal(t, "", h.config.Workspace)
+}
@@ -9,7 +9,10 @@ func init() {
 func initKeys() {
 	tcell.KeyNames[tcell.Key(KeyHelp)] = "?"
 	tcell.KeyNames[tcell.Key(KeySlash)] = "/"
+	tcell.KeyNames[tcell.Key(KeyColon)] = ":"
 	tcell.KeyNames[tcell.Key(KeySpace)] = "space"
+	tcell.KeyNames[tcell.Key(KeyLeftBracket)] = "["
+	tcell.KeyNames[tcell.Key(KeyRightBracket)] = "]"
 
 	initNumbKeys()
 	initStdKeys()
@@ -77,6 +80,9 @@ const (
 	KeySlash = 47
 	KeyColon = 58
 	KeySpace = 32
+
+	KeyLeftBracket  = 91
+	KeyRightBracket = 93
 )
 
 // Define Shift Keys.
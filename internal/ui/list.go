@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -14,11 +18,11 @@ type ListPageSource interface {
 	Name() string
 	NameList() string
 
-	RenderHeader(table *tview.Table)
+	Columns() []Column
 	RenderRows(table *tview.Table)
 
 	SupportsSearch() bool
-	Search(searchText string, pageNumber int) error
+	Search(ctx context.Context, searchText string, pageNumber int) error
 
 	Empty() bool
 	CurrentPage() int
@@ -26,6 +30,33 @@ type ListPageSource interface {
 	TotalPages() int
 }
 
+// Column describes a column of a list page. The rows are sorted by the text
+// of the column cells, comparing the numbers in them as numbers so the counts
+// and the versions are in order, or by the cell references if they are times.
+type Column struct {
+	Name string
+
+	// Sorted sorts the rows by the column, ascending, until another column is
+	// sorted.
+	Sorted bool
+}
+
+// sortKeys are the keys sorting the rows by the column with the same number.
+var sortKeys = []tcell.Key{KeyShift1, KeyShift2, KeyShift3, KeyShift4, KeyShift5, KeyShift6, KeyShift7, KeyShift8, KeyShift9}
+
+// SettledSource is implemented by the list sources showing data that keeps
+// changing, like the workspaces run status, to refresh the list until it
+// settles.
+type SettledSource interface {
+	Settled() bool
+}
+
+// KeyBindingSource is implemented by the list sources with their own
+// actions, bound along with the list actions.
+type KeyBindingSource interface {
+	BindKeys(l *ListPage) KeyActions
+}
+
 type ListPage struct {
 	app    *App
 	source ListPageSource
@@ -37,6 +68,13 @@ type ListPage struct {
 	searchInput *tview.InputField
 	searching   bool
 
+	// filterInput filters the rows shown, unlike the search which filters the
+	// rows read.
+	filterInput *tview.InputField
+
+	sortColumn     int
+	sortDescending bool
+
 	currentItem int
 }
 
@@ -49,15 +87,29 @@ func NewListPage(app *App, source ListPageSource) Page {
 		table:       tview.NewTable(),
 		pagination:  tview.NewTextView(),
 		searchInput: tview.NewInputField(),
+		filterInput: tview.NewInputField(),
 
+		sortColumn:  -1,
 		currentItem: 1,
 	}
 
+	for i, c := range source.Columns() {
+		if c.Sorted {
+			l.sortColumn = i
+		}
+	}
+
 	headerFlex := tview.NewFlex()
 	headerFlex.AddItem(l.searchInput, 0, 1, false)
+	headerFlex.AddItem(l.filterInput, 0, 1, false)
 	headerFlex.AddItem(l.pagination, 0, 1, false)
 
 	l.searchInput.SetFieldBackgroundColor(l.GetBackgroundColor())
+	l.filterInput.SetFieldBackgroundColor(l.GetBackgroundColor())
+	l.filterInput.SetChangedFunc(func(text string) {
+		l.table.Select(1, 0)
+		l.View()
+	})
 	l.pagination.SetTextAlign(tview.AlignRight)
 
 	l.AddItem(headerFlex, 2, 0, false).
@@ -67,35 +119,126 @@ func NewListPage(app *App, source ListPageSource) Page {
 	return &l
 }
 
-func (l *ListPage) Load() error {
-	return l.source.Search("", -1)
+func (l *ListPage) Load(ctx context.Context) error {
+	return l.source.Search(ctx, "", -1)
 }
 
 func (l *ListPage) View() string {
 	l.table.Clear()
 	l.table.SetSelectable(true, false)
 
-	l.source.RenderHeader(l.table)
+	l.renderHeader()
 	if l.source.Empty() {
 		return fmt.Sprintf("no %s found", l.source.NameList())
 	}
 
 	l.source.RenderRows(l.table)
+	shown := l.sortAndFilterRows()
 
 	l.table.SetSelectionChangedFunc(func(row, column int) {
 		l.currentItem = row
 	})
 
-	l.pagination.SetText(
-		fmt.Sprintf("page %d of %d, total %s: %d",
-			l.source.CurrentPage(),
-			l.source.TotalPages(),
-			l.source.NameList(),
-			l.source.TotalCount()))
+	pagination := fmt.Sprintf("page %d of %d, total %s: %d",
+		l.source.CurrentPage(),
+		l.source.TotalPages(),
+		l.source.NameList(),
+		l.source.TotalCount())
+	if l.filterInput.GetText() != "" {
+		pagination += fmt.Sprintf(", shown: %d", shown)
+	}
+	l.pagination.SetText(pagination)
 
 	return "workspaces loaded"
 }
 
+// renderHeader renders the column names, with the order of the sorted one.
+func (l *ListPage) renderHeader() {
+	for i, c := range l.source.Columns() {
+		name := c.Name
+		if i == l.sortColumn {
+			order := "▲"
+			if l.sortDescending {
+				order = "▼"
+			}
+			name = fmt.Sprintf("%s %s", name, order)
+		}
+		l.table.SetCell(0, i, tview.NewTableCell(name).SetSelectable(false))
+	}
+}
+
+// sortAndFilterRows sorts the rows rendered by the source by the sorted
+// column and removes the rows without the filter text in any cell, returning
+// how many rows are left.
+func (l *ListPage) sortAndFilterRows() int {
+	filter := strings.ToLower(l.filterInput.GetText())
+
+	rows := [][]*tview.TableCell{}
+	for r := 1; r < l.table.GetRowCount(); r++ {
+		row := make([]*tview.TableCell, l.table.GetColumnCount())
+		matches := filter == ""
+		for c := range row {
+			row[c] = l.table.GetCell(r, c)
+			if strings.Contains(strings.ToLower(row[c].Text), filter) {
+				matches = true
+			}
+		}
+		if matches {
+			rows = append(rows, row)
+		}
+	}
+
+	if l.sortColumn >= 0 && l.sortColumn < l.table.GetColumnCount() {
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, b := rows[i][l.sortColumn], rows[j][l.sortColumn]
+			if l.sortDescending {
+				return compareCells(b, a) < 0
+			}
+			return compareCells(a, b) < 0
+		})
+	}
+
+	for r := l.table.GetRowCount() - 1; r > 0; r-- {
+		l.table.RemoveRow(r)
+	}
+	for i, row := range rows {
+		for c, cell := range row {
+			l.table.SetCell(i+1, c, cell)
+		}
+	}
+	return len(rows)
+}
+
+// SortBy sorts the rows by the column, ascending, or reverses the order if the
+// rows are sorted by it.
+func (l *ListPage) SortBy(column int) {
+	if l.sortColumn == column {
+		l.sortDescending = !l.sortDescending
+	} else {
+		l.sortColumn = column
+		l.sortDescending = false
+	}
+	l.View()
+}
+
+func (l *ListPage) RefreshFunc() func(context.Context) error {
+	searchText := l.searchInput.GetText()
+	pageNumber := l.source.CurrentPage()
+	return func(ctx context.Context) error {
+		return l.source.Search(ctx, searchText, pageNumber)
+	}
+}
+
+// Repaint renders the rows again, the table keeps its selection when cleared.
+func (l *ListPage) Repaint() {
+	l.View()
+}
+
+func (l *ListPage) Settled() bool {
+	source, ok := l.source.(SettledSource)
+	return !ok || l.source.Empty() || source.Settled()
+}
+
 func (l *ListPage) BindKeys() KeyActions {
 	aa := KeyActions{
 		tcell.KeyEnter: NewKeyAction(fmt.Sprintf("select %s", l.source.Name()), l.actionSelectWorkspace, true),
@@ -106,10 +249,22 @@ func (l *ListPage) BindKeys() KeyActions {
 	if l.source.SupportsSearch() {
 		aa.Add(KeyActions{
 			KeySlash:     NewKeyAction(fmt.Sprintf("search %s", l.source.NameList()), l.actionSearch, true),
-			tcell.KeyEsc: NewKeyAction("cancel search if search results are active", l.actionCancelSearch, true),
+			tcell.KeyEsc: NewKeyAction("cancel search or go back", l.actionCancelSearch, true),
 		})
 	}
 
+	for i, c := range l.source.Columns() {
+		if i == len(sortKeys) {
+			break
+		}
+		aa[sortKeys[i]] = NewKeyAction(fmt.Sprintf("sort by %s", strings.ToLower(c.Name)), l.actionSortBy(i), true)
+	}
+	aa[KeyF] = NewKeyAction(fmt.Sprintf("filter %s shown", l.source.NameList()), l.actionFilter, true)
+
+	if source, ok := l.source.(KeyBindingSource); ok {
+		aa.Add(source.BindKeys(l))
+	}
+
 	return aa
 }
 
@@ -130,16 +285,47 @@ func (l *ListPage) actionSelectWorkspace(ek *tcell.EventKey) *tcell.EventKey {
 		return ek
 	}
 
+	// the filter may leave no rows or fewer than the selected one
+	if l.currentItem < 1 || l.currentItem >= l.table.GetRowCount() {
+		return nil
+	}
+
 	return l.source.ActionSelectWorkspace(l.table, l.currentItem)(ek)
 }
 
+func (l *ListPage) actionSortBy(column int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		l.SortBy(column)
+		return nil
+	}
+}
+
+// actionFilter focus the filter input, the rows are filtered as the text is
+// typed. Enter keeps the filter and escape clears it.
+func (l *ListPage) actionFilter(ek *tcell.EventKey) *tcell.EventKey {
+	l.filterInput.SetFieldBackgroundColor(tview.Styles.ContrastBackgroundColor)
+	l.filterInput.SetLabel("filter: ")
+	l.filterInput.SetDoneFunc(func(key tcell.Key) {
+		l.filterInput.SetFieldBackgroundColor(l.GetBackgroundColor())
+		if key == tcell.KeyEscape {
+			l.filterInput.SetText("")
+		}
+		if l.filterInput.GetText() == "" {
+			l.filterInput.SetLabel("")
+		}
+		l.app.SetFocus(l.table)
+	})
+	l.app.SetFocus(l.filterInput)
+	return nil
+}
+
 func (l *ListPage) actionPaginationNextPage(ek *tcell.EventKey) *tcell.EventKey {
-	go l.app.ExecPageWithLoadFunc(l, l.loadNextPageFunc(), false)
+	l.app.ExecPageWithLoadFunc(l, l.loadNextPageFunc(), false)
 	return nil
 }
 
 func (l *ListPage) actionPaginationPrevPage(ek *tcell.EventKey) *tcell.EventKey {
-	go l.app.ExecPageWithLoadFunc(l, l.loadPrevPageFunc(), false)
+	l.app.ExecPageWithLoadFunc(l, l.loadPrevPageFunc(), false)
 	return nil
 }
 
@@ -155,7 +341,7 @@ func (l *ListPage) actionSearch(ek *tcell.EventKey) *tcell.EventKey {
 			l.searchInput.SetLabel("")
 			l.searchInput.SetText("")
 		case tcell.KeyEnter:
-			go l.app.ExecPageWithLoadFunc(l, l.loadSearchFunc(), false)
+			l.app.ExecPageWithLoadFunc(l, l.loadSearchFunc(), false)
 		}
 		l.searching = false
 		l.app.SetFocus(l.table)
@@ -166,39 +352,97 @@ func (l *ListPage) actionSearch(ek *tcell.EventKey) *tcell.EventKey {
 
 func (l *ListPage) actionCancelSearch(ek *tcell.EventKey) *tcell.EventKey {
 	if l.searchInput.GetText() == "" {
-		return ek
+		return l.app.goBack(ek)
 	}
 
 	l.searchInput.SetFieldBackgroundColor(l.GetBackgroundColor())
 	l.searchInput.SetLabel("")
 	l.searchInput.SetText("")
-	go l.app.ExecPageWithLoadFunc(l, l.loadSearchFunc(), false)
+	l.app.ExecPageWithLoadFunc(l, l.loadSearchFunc(), false)
 
 	return nil
 }
 
-func (l *ListPage) loadNextPageFunc() func() error {
-	return func() error {
+func (l *ListPage) loadNextPageFunc() func(context.Context) error {
+	searchText := l.searchInput.GetText()
+	return func(ctx context.Context) error {
 		pageToLoad := l.source.CurrentPage() + 1
 		if pageToLoad > l.source.TotalPages() {
 			pageToLoad = 1
 		}
-		return l.source.Search(l.searchInput.GetText(), pageToLoad)
+		return l.source.Search(ctx, searchText, pageToLoad)
 	}
 }
 
-func (l *ListPage) loadPrevPageFunc() func() error {
-	return func() error {
+func (l *ListPage) loadPrevPageFunc() func(context.Context) error {
+	searchText := l.searchInput.GetText()
+	return func(ctx context.Context) error {
 		pageToLoad := l.source.CurrentPage() - 1
 		if pageToLoad < 1 {
 			pageToLoad = l.source.TotalPages()
 		}
-		return l.source.Search(l.searchInput.GetText(), pageToLoad)
+		return l.source.Search(ctx, searchText, pageToLoad)
+	}
+}
+
+func (l *ListPage) loadSearchFunc() func(context.Context) error {
+	searchText := l.searchInput.GetText()
+	return func(ctx context.Context) error {
+		return l.source.Search(ctx, searchText, -1)
+	}
+}
+
+// compareCells compares the cells by their references if both are times, or
+// else by their texts.
+func compareCells(a, b *tview.TableCell) int {
+	at, aok := a.GetReference().(time.Time)
+	bt, bok := b.GetReference().(time.Time)
+	if aok && bok {
+		switch {
+		case at.Before(bt):
+			return -1
+		case at.After(bt):
+			return 1
+		}
+		return 0
 	}
+	return naturalCompare(a.Text, b.Text)
 }
 
-func (l *ListPage) loadSearchFunc() func() error {
-	return func() error {
-		return l.source.Search(l.searchInput.GetText(), -1)
+// naturalCompare compares the texts ignoring the case and comparing the
+// digits in them as numbers, so "1.9.0" comes before "1.10.0".
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		ca, cb := leadingChunk(a), leadingChunk(b)
+		a, b = a[len(ca):], b[len(cb):]
+
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			ca, cb = na, nb
+		}
+		if c := strings.Compare(ca, cb); c != 0 {
+			return c
+		}
 	}
+	return len(a) - len(b)
+}
+
+// leadingChunk returns the digits or the other characters at the start of the
+// text.
+func leadingChunk(text string) string {
+	digits := isDigit(text[0])
+	for i := 1; i < len(text); i++ {
+		if isDigit(text[i]) != digits {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
 }
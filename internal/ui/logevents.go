@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// Resource states in the structured logs.
+const (
+	resourcePlanned  = "planned"
+	resourceDrifted  = "drifted"
+	resourceApplying = "applying"
+	resourceApplied  = "applied"
+	resourceErrored  = "errored"
+)
+
+// logActions are the icons and colors of the resource change actions.
+var logActions = map[string][2]string{
+	"create":  {"+", "green"},
+	"update":  {"~", "yellow"},
+	"replace": {"-/+", "fuchsia"},
+	"delete":  {"-", "red"},
+	"read":    {"<=", "aqua"},
+	"move":    {"->", "blue"},
+}
+
+// logResource is the progress of a resource change, from the plan to the end
+// of the apply.
+type logResource struct {
+	addr    string
+	action  string
+	status  string
+	started time.Time
+	elapsed time.Duration
+}
+
+// structuredLog groups the terraform machine readable log events by resource
+// address, keeping the other messages, the change summary and the
+// diagnostics apart.
+type structuredLog struct {
+	messages    []string
+	resources   []*logResource
+	byAddr      map[string]*logResource
+	summaries   []string
+	diagnostics []string
+	events      int
+}
+
+func newStructuredLog() *structuredLog {
+	return &structuredLog{byAddr: map[string]*logResource{}}
+}
+
+// Empty is true while no JSON log events were added, e.g. for runs not using
+// the structured run output.
+func (l *structuredLog) Empty() bool {
+	return l.events == 0
+}
+
+// Add adds a log line, which is kept as a message if it is not a JSON log
+// event.
+func (l *structuredLog) Add(line string, monochrome bool) {
+	entry := applyLogEntry{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		l.messages = append(l.messages, renderLogText(line, monochrome))
+		return
+	}
+	l.events++
+
+	switch entry.Type {
+	case "planned_change", "resource_drift":
+		r := l.resource(entry.Change.Resource.Addr)
+		if r == nil {
+			break
+		}
+		r.action = entry.Change.Action
+		r.status = resourcePlanned
+		if entry.Type == "resource_drift" {
+			r.status = resourceDrifted
+		}
+		return
+	case "apply_start", "apply_progress", "apply_complete", "apply_errored":
+		r := l.resource(entry.Hook.Resource.Addr)
+		if r == nil {
+			break
+		}
+		l.applyHook(r, entry)
+		return
+	case "refresh_start", "refresh_complete":
+		// the refresh of every resource in the state is too much noise
+		return
+	case "change_summary":
+		l.summaries = append(l.summaries, paintLog("::b", renderLogText(entry.Message, monochrome), monochrome))
+		return
+	case "diagnostic":
+		l.diagnostics = append(l.diagnostics, formatDiagnostic(entry, monochrome))
+		return
+	}
+
+	l.messages = append(l.messages, renderLogText(entry.Message, monochrome))
+}
+
+// resource returns the resource with the address, adding it if it is new, or
+// nil if the address is empty.
+func (l *structuredLog) resource(addr string) *logResource {
+	if addr == "" {
+		return nil
+	}
+
+	r, ok := l.byAddr[addr]
+	if !ok {
+		r = &logResource{addr: addr}
+		l.byAddr[addr] = r
+		l.resources = append(l.resources, r)
+	}
+	return r
+}
+
+func (l *structuredLog) applyHook(r *logResource, entry applyLogEntry) {
+	if entry.Hook.Action != "" {
+		r.action = entry.Hook.Action
+	}
+
+	timestamp, _ := time.Parse(time.RFC3339Nano, entry.Timestamp)
+	switch entry.Type {
+	case "apply_start":
+		r.status = resourceApplying
+		r.started = timestamp
+		r.elapsed = 0
+		return
+	case "apply_complete":
+		r.status = resourceApplied
+	case "apply_errored":
+		r.status = resourceErrored
+	}
+
+	switch {
+	case entry.Hook.ElapsedSeconds > 0:
+		r.elapsed = time.Duration(entry.Hook.ElapsedSeconds * float64(time.Second))
+	case !r.started.IsZero() && !timestamp.IsZero():
+		r.elapsed = timestamp.Sub(r.started)
+	}
+}
+
+// Render returns the messages, the resources, the change summaries and the
+// diagnostics, ready to be written to a text view with dynamic colors.
+func (l *structuredLog) Render(monochrome bool) string {
+	lines := append([]string{}, l.messages...)
+
+	if len(l.resources) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+
+		width := 0
+		for _, r := range l.resources {
+			if len(r.addr) > width {
+				width = len(r.addr)
+			}
+		}
+		for _, r := range l.resources {
+			lines = append(lines, formatLogResource(r, width, monochrome))
+		}
+	}
+
+	if len(l.summaries) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, l.summaries...)
+	}
+
+	if len(l.diagnostics) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, l.diagnostics...)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatLogResource(r *logResource, width int, monochrome bool) string {
+	icon, color := " ", "foreground"
+	if a, ok := logActions[r.action]; ok {
+		icon, color = a[0], a[1]
+	}
+
+	status := r.status
+	elapsed := r.elapsed.Round(time.Second)
+	switch r.status {
+	case resourceApplying:
+		status = "⏳ applying"
+	case resourceApplied:
+		status = fmt.Sprintf("✅ applied in %s", elapsed)
+	case resourceErrored:
+		status = paintLog("red", fmt.Sprintf("❌ errored after %s", elapsed), monochrome)
+	case resourceDrifted:
+		status = paintLog("yellow", "drifted", monochrome)
+	}
+
+	return fmt.Sprintf("%s %s %-8s %s",
+		paintLog(color, fmt.Sprintf("%3s", icon), monochrome),
+		tview.Escape(fmt.Sprintf("%-*s", width, r.addr)),
+		r.action,
+		status)
+}
+
+// formatDiagnostic returns the diagnostic message and detail, colored by the
+// log level.
+func formatDiagnostic(entry applyLogEntry, monochrome bool) string {
+	color := "foreground"
+	switch entry.Level {
+	case "error":
+		color = "red"
+	case "warn":
+		color = "yellow"
+	}
+
+	lines := []string{paintLog(color, renderLogText(entry.Message, monochrome), monochrome)}
+	if entry.Diagnostic.Detail != "" {
+		for _, d := range strings.Split(entry.Diagnostic.Detail, "\n") {
+			lines = append(lines, "    "+renderLogText(d, monochrome))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// paintLog wraps the text with the color tag, unless monochrome.
+func paintLog(color, text string, monochrome bool) string {
+	if monochrome {
+		return text
+	}
+	return fmt.Sprintf("[%s]%s[-:-:-]", color, text)
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+var structuredPlanLogs = []string{
+	`Terraform v1.1.9`,
+	`{"@level":"info","@message":"aws_s3_bucket.b: Refreshing state...","type":"refresh_start","hook":{"resource":{"addr":"aws_s3_bucket.b"}}}`,
+	`{"@level":"info","@message":"aws_s3_bucket.b: Plan to create","type":"planned_change","change":{"resource":{"addr":"aws_s3_bucket.b"},"action":"create"}}`,
+	`{"@level":"info","@message":"aws_instance.web[0]: Plan to replace","type":"planned_change","change":{"resource":{"addr":"aws_instance.web[0]"},"action":"replace"}}`,
+	`{"@level":"info","@message":"Plan: 2 to add, 0 to change, 1 to destroy.","type":"change_summary"}`,
+}
+
+var structuredApplyLogs = []string{
+	`{"@level":"info","@message":"aws_s3_bucket.b: Creating...","@timestamp":"2022-03-07T10:00:00.000000Z","type":"apply_start","hook":{"resource":{"addr":"aws_s3_bucket.b"},"action":"create"}}`,
+	`{"@level":"info","@message":"aws_instance.web[0]: Destroying...","@timestamp":"2022-03-07T10:00:00.000000Z","type":"apply_start","hook":{"resource":{"addr":"aws_instance.web[0]"},"action":"delete"}}`,
+	`{"@level":"info","@message":"aws_s3_bucket.b: Creation complete after 3s","@timestamp":"2022-03-07T10:00:03.000000Z","type":"apply_complete","hook":{"resource":{"addr":"aws_s3_bucket.b"},"action":"create"}}`,
+	`{"@level":"error","@message":"aws_instance.web[0]: Destruction errored after 12s","type":"apply_errored","hook":{"resource":{"addr":"aws_instance.web[0]"},"action":"delete","elapsed_seconds":12}}`,
+	`{"@level":"warn","@message":"Warning: deprecated argument","type":"diagnostic","diagnostic":{"severity":"warning","summary":"deprecated argument"}}`,
+	`{"@level":"error","@message":"Error: deleting instance","type":"diagnostic","diagnostic":{"severity":"error","summary":"deleting instance","detail":"InvalidInstanceID\nretry later"}}`,
+}
+
+func renderStructuredLog(lines []string, monochrome bool) string {
+	l := newStructuredLog()
+	for _, line := range lines {
+		l.Add(line, monochrome)
+	}
+	return l.Render(monochrome)
+}
+
+func TestStructuredLog(t *testing.T) {
+	testCases := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{
+			name:  "plan",
+			lines: structuredPlanLogs,
+			expected: []string{
+				"Terraform v1.1.9",
+				"",
+				"  + aws_s3_bucket.b     create   planned",
+				// the brackets are escaped for the text views
+				"-/+ aws_instance.web[0[] replace  planned",
+				"",
+				"Plan: 2 to add, 0 to change, 1 to destroy.",
+			},
+		},
+		{
+			name:  "apply",
+			lines: append(append([]string{}, structuredPlanLogs...), structuredApplyLogs...),
+			expected: []string{
+				"Terraform v1.1.9",
+				"",
+				"  + aws_s3_bucket.b     create   ✅ applied in 3s",
+				"  - aws_instance.web[0[] delete   ❌ errored after 12s",
+				"",
+				"Plan: 2 to add, 0 to change, 1 to destroy.",
+				"",
+				"Warning: deprecated argument",
+				"Error: deleting instance",
+				"    InvalidInstanceID",
+				"    retry later",
+			},
+		},
+		{
+			name: "apply in progress",
+			lines: []string{
+				`{"@level":"info","@message":"aws_s3_bucket.b: Creating...","type":"apply_start","hook":{"resource":{"addr":"aws_s3_bucket.b"},"action":"create"}}`,
+			},
+			expected: []string{
+				"  + aws_s3_bucket.b create   ⏳ applying",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered := renderStructuredLog(tc.lines, true)
+			assert.Equal(t, strings.Join(tc.expected, "\n")+"\n", rendered)
+		})
+	}
+}
+
+func TestStructuredLogColors(t *testing.T) {
+	rendered := renderStructuredLog(append(append([]string{}, structuredPlanLogs...), structuredApplyLogs...), false)
+
+	assert.Contains(t, rendered, "[green]  +[-:-:-] aws_s3_bucket.b")
+	assert.Contains(t, rendered, "[red]  -[-:-:-] aws_instance.web[0[]")
+	assert.Contains(t, rendered, "[red]❌ errored after 12s[-:-:-]")
+	assert.Contains(t, rendered, "[yellow]Warning: deprecated argument[-:-:-]")
+	assert.Contains(t, rendered, "[red]Error: deleting instance[-:-:-]")
+}
+
+func TestStructuredLogEmpty(t *testing.T) {
+	l := newStructuredLog()
+	l.Add("Terraform v1.1.9", false)
+	assert.True(t, l.Empty())
+
+	l.Add(`{"@level":"info","@message":"Terraform 1.1.9","type":"version"}`, false)
+	assert.False(t, l.Empty())
+}
+
+func TestRunPageStructuredLogs(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanLogs("plan-run-new", strings.Join(structuredPlanLogs, "\n"))
+	c.SetApplyLogs("apply-run-new", strings.Join(structuredApplyLogs, "\n"))
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showRun(h, "run-new")
+
+	h.waitFor("+ aws_s3_bucket.b     create   ✅ applied in 3s")
+	h.waitFor("- aws_instance.web[0] delete   ❌ errored after 12s")
+	h.waitForGone("Creation complete after 3s")
+
+	h.pressRune('s')
+	h.waitFor("apply ✅ 6 lines | raw | follow")
+	h.waitFor("aws_s3_bucket.b: Creation complete after 3s")
+	h.waitFor("aws_s3_bucket.b: Plan to create")
+	h.waitForGone("✅ applied in 3s")
+
+	h.pressRune('s')
+	h.waitFor("apply ✅ 6 lines | follow")
+	h.waitFor("✅ applied in 3s")
+}
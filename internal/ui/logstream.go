@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const logChunkSize = 64 * 1024
+
+// structuredRenderInterval throttles rendering the structured logs, rendered
+// whole as they are grouped by resource, while the lines arrive.
+const structuredRenderInterval = 200 * time.Millisecond
+
+// ansiRX matches the terminal escape sequences, like the colors.
+var ansiRX = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// logStream follows the logs of a plan or an apply, appending the lines to
+// the pane as they arrive. The client log readers keep polling the API until
+// the phase finishes.
+//
+// The JSON log events are shown grouped by resource, unless the raw text is
+// shown or the logs have no JSON events.
+type logStream struct {
+	app   *App
+	phase string
+	view  *tview.TextView
+	open  func(context.Context) (io.Reader, error)
+
+	follow  bool
+	raw     bool
+	lines   int
+	running bool
+	done    bool
+	err     error
+
+	text       bytes.Buffer
+	events     *structuredLog
+	structured bool
+	// renderQueued is set while the structured logs wait to be rendered.
+	renderQueued bool
+}
+
+func newLogStream(app *App, phase string, view *tview.TextView, open func(context.Context) (io.Reader, error)) *logStream {
+	return &logStream{
+		app:    app,
+		phase:  phase,
+		view:   view,
+		open:   open,
+		follow: true,
+	}
+}
+
+// Start reads the logs from the beginning in the background until they end or
+// the context is cancelled. It must be called on the UI goroutine.
+func (s *logStream) Start(ctx context.Context) {
+	s.view.Clear()
+	s.text.Reset()
+	s.events = newStructuredLog()
+	s.structured = false
+	s.lines = 0
+	s.running = true
+	s.done = false
+	s.err = nil
+	s.updateTitle()
+
+	go func() {
+		err := s.read(ctx)
+
+		s.app.QueueUpdateDraw(func() {
+			s.running = false
+			if err == nil {
+				s.done = true
+			} else if ctx.Err() == nil {
+				s.err = err
+				s.write([]string{fmt.Sprintf("%s details could not be loaded: %s", s.phase, err.Error())})
+			}
+			s.updateTitle()
+		})
+	}()
+}
+
+// Stopped is true when the stream needs to be started again to get the
+// logs, because it failed or was cancelled before the end.
+func (s *logStream) Stopped() bool {
+	return !s.running && !s.done
+}
+
+// Settled is true when the logs were read until the end or failed.
+func (s *logStream) Settled() bool {
+	return s.done || (!s.running && s.err != nil)
+}
+
+// SetFollow turns auto scrolling to the latest lines on or off.
+func (s *logStream) SetFollow(follow bool) {
+	s.follow = follow
+	if follow {
+		s.view.ScrollToEnd()
+	} else {
+		s.view.ScrollTo(s.view.GetScrollOffset())
+	}
+	s.updateTitle()
+}
+
+// SetRaw switches between the raw text and the structured logs.
+func (s *logStream) SetRaw(raw bool) {
+	s.raw = raw
+	if s.events != nil {
+		s.render(nil)
+	}
+	s.updateTitle()
+}
+
+// read appends the complete lines of each chunk read, keeping the partial
+// last line for the next chunk.
+func (s *logStream) read(ctx context.Context) error {
+	reader, err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, logChunkSize)
+	pending := []byte{}
+	for {
+		n, err := reader.Read(buf)
+		pending = append(pending, buf[:n]...)
+
+		if errors.Is(err, io.EOF) {
+			if len(pending) > 0 {
+				pending = append(pending, '\n')
+			}
+			s.appendLines(ctx, pending)
+			return nil
+		}
+
+		last := bytes.LastIndexByte(pending, '\n')
+		if last >= 0 {
+			s.appendLines(ctx, pending[:last+1])
+			pending = append([]byte{}, pending[last+1:]...)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *logStream) appendLines(ctx context.Context, chunk []byte) {
+	if len(chunk) == 0 {
+		return
+	}
+
+	lines := []string{}
+	for _, line := range bytes.SplitAfter(chunk, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		lines = append(lines, string(bytes.TrimRight(line, "\r\n")))
+	}
+
+	s.app.QueueUpdateDraw(func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.write(lines)
+		s.lines += len(lines)
+		s.updateTitle()
+	})
+}
+
+// write adds the lines to the raw text and to the structured logs, and shows
+// them. It must be called on the UI goroutine.
+func (s *logStream) write(lines []string) {
+	monochrome := s.app.config.MonochromeLogs
+
+	text := bytes.Buffer{}
+	for _, line := range lines {
+		text.WriteString(fmt.Sprintln(formatLogLine([]byte(line), monochrome)))
+		s.events.Add(line, monochrome)
+	}
+	s.text.Write(text.Bytes())
+
+	s.render(text.Bytes())
+}
+
+// render shows the structured logs or the raw text, appending only the new
+// text when the raw text is already shown. The appended text is nil to show
+// everything again. The structured logs are rendered at most once per
+// interval while the lines are appended.
+func (s *logStream) render(appended []byte) {
+	structured := !s.raw && !s.events.Empty()
+
+	switch {
+	case structured && appended != nil:
+		s.queueRender()
+		return
+	case structured:
+		s.view.SetText(s.events.Render(s.app.config.MonochromeLogs))
+	case s.structured || appended == nil:
+		s.view.SetText(s.text.String())
+	default:
+		s.view.Write(appended)
+	}
+	s.structured = structured
+
+	if s.follow {
+		s.view.ScrollToEnd()
+	}
+}
+
+// queueRender renders the logs again after the render interval, unless
+// already queued. It must be called on the UI goroutine.
+func (s *logStream) queueRender() {
+	if s.renderQueued {
+		return
+	}
+	s.renderQueued = true
+
+	go func() {
+		time.Sleep(structuredRenderInterval)
+		s.app.QueueUpdateDraw(func() {
+			s.renderQueued = false
+			s.render(nil)
+		})
+	}()
+}
+
+// updateTitle shows the phase progress in the pane title.
+func (s *logStream) updateTitle() {
+	status := "⏳"
+	switch {
+	case s.done:
+		status = "✅"
+	case s.err != nil:
+		status = "❌"
+	case !s.running:
+		status = "⏸"
+	}
+
+	flags := ""
+	if s.raw {
+		flags += " | raw"
+	}
+	if s.follow {
+		flags += " | follow"
+	}
+
+	s.view.SetTitle(fmt.Sprintf(" %s %s %d lines%s ", s.phase, status, s.lines, flags))
+}
+
+// formatLogLine returns the message of the JSON log lines and the other lines
+// as they are, ready to be written to a text view with dynamic colors.
+func formatLogLine(line []byte, monochrome bool) string {
+	log := applyLogEntry{}
+	if err := json.Unmarshal(line, &log); err != nil {
+		return renderLogText(string(line), monochrome)
+	}
+	return renderLogText(log.Message, monochrome)
+}
+
+// renderLogText escapes the text, so brackets are not taken as color tags,
+// and translates the terminal colors to color tags or strips them when
+// monochrome.
+func renderLogText(text string, monochrome bool) string {
+	if monochrome {
+		return tview.Escape(ansiRX.ReplaceAllString(text, ""))
+	}
+	return tview.TranslateANSI(tview.Escape(text))
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestFormatLogLine(t *testing.T) {
+	testCases := []struct {
+		name       string
+		line       string
+		monochrome bool
+		expected   string
+	}{
+		{
+			name:     "plain text",
+			line:     "Terraform v1.1.9",
+			expected: "Terraform v1.1.9",
+		},
+		{
+			name:     "json message",
+			line:     `{"@level":"info","@message":"Plan: 1 to add","type":"change_summary"}`,
+			expected: "Plan: 1 to add",
+		},
+		{
+			name:     "ansi colors",
+			line:     "\x1b[32m+\x1b[0m resource",
+			expected: "[green:]+[-:-:-] resource",
+		},
+		{
+			name:       "ansi colors monochrome",
+			line:       "\x1b[32m+\x1b[0m resource",
+			monochrome: true,
+			expected:   "+ resource",
+		},
+		{
+			name:     "brackets are escaped",
+			line:     "\x1b[1mStill creating... [10s elapsed]\x1b[0m",
+			expected: "[::b]Still creating... [10s elapsed[][-:-:-]",
+		},
+		{
+			name:       "brackets are escaped monochrome",
+			line:       "Still creating... [10s elapsed]",
+			monochrome: true,
+			expected:   "Still creating... [10s elapsed[]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatLogLine([]byte(tc.line), tc.monochrome))
+		})
+	}
+}
+
+func TestRunPageANSIColors(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanLogs("plan-run-new", "\x1b[32m+\x1b[0m resource \"aws_s3_bucket\" \"b\" {\nStill creating... [10s elapsed]")
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showRun(h, "run-new")
+
+	h.waitFor(`+ resource "aws_s3_bucket" "b" {`)
+	h.waitFor("Still creating... [10s elapsed]")
+	fg, _, _ := h.styleOf(`+ resource "aws_s3_bucket"`).Decompose()
+	assert.Equal(t, tcell.ColorGreen, fg)
+}
+
+func TestRunPageMonochromeLogs(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanLogs("plan-run-new", "\x1b[32m+\x1b[0m resource \"aws_s3_bucket\" \"b\" {")
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod", MonochromeLogs: true}
+	h := newHarness(t, cfg, c)
+	showRun(h, "run-new")
+
+	h.waitFor(`+ resource "aws_s3_bucket" "b" {`)
+	fg, _, _ := h.styleOf(`+ resource "aws_s3_bucket"`).Decompose()
+	assert.NotEqual(t, tcell.ColorGreen, fg)
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gdamore/tcell/v2"
@@ -13,25 +14,21 @@ import (
 const OrganizationsPageName string = "organizations"
 
 type OrganizationsPageSource struct {
-	app  *App
-	orgs *tfe.OrganizationList
+	app       *App
+	tfeClient client.TFEClient
+	orgs      *tfe.OrganizationList
 }
 
-func NewOrganizationsPage(app *App) Page {
-	return NewListPage(app, &OrganizationsPageSource{app: app})
+func NewOrganizationsPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &OrganizationsPageSource{app: app, tfeClient: tfeClient})
 }
 
 func (o *OrganizationsPageSource) SupportsSearch() bool {
 	return false
 }
 
-func (o *OrganizationsPageSource) Search(searchText string, pageNumber int) error {
-	tfeClient, err := client.NewTFEClient()
-	if err != nil {
-		return fmt.Errorf("error creating the TFE client: %w", err)
-	}
-
-	orgs, err := tfeClient.ListOrganizations(pageNumber)
+func (o *OrganizationsPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	orgs, err := o.tfeClient.ListOrganizations(ctx, pageNumber)
 	if err != nil {
 		return fmt.Errorf("error listing the organization: %w", err)
 	}
@@ -40,10 +37,12 @@ func (o *OrganizationsPageSource) Search(searchText string, pageNumber int) erro
 	return nil
 }
 
-func (o *OrganizationsPageSource) RenderHeader(table *tview.Table) {
-	table.SetCell(0, 0, tview.NewTableCell("ID").SetSelectable(false))
-	table.SetCell(0, 1, tview.NewTableCell("NAME").SetSelectable(false))
-	table.SetCell(0, 2, tview.NewTableCell("E-MAIL").SetSelectable(false))
+func (o *OrganizationsPageSource) Columns() []Column {
+	return []Column{
+		{Name: "ID"},
+		{Name: "NAME"},
+		{Name: "E-MAIL"},
+	}
 }
 
 func (o *OrganizationsPageSource) RenderRows(table *tview.Table) {
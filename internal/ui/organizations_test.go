@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestOrganizationsPage(t *testing.T) {
+	h := newHarness(t, &config.Config{}, newFakeClient())
+
+	h.pressKey(tcell.KeyCtrlO)
+	h.waitFor("admin@globex.example.com")
+	h.waitFor("page 1 of 1, total organizations: 2")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<workspaces>")
+	h.waitFor("app-prod")
+	assert.Equal(t, "acme", h.config.Organization)
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v2"
+)
+
+type workspaceOutput struct {
+	Name      string      `yaml:"Name"`
+	Type      string      `yaml:"Type"`
+	Sensitive bool        `yaml:"Sensitive"`
+	Value     interface{} `yaml:"Value"`
+}
+
+// showOutputs adds the outputs of the current state to the list, with the
+// sensitive ones marked.
+func (w *WorkspacePage) showOutputs(list *tview.List) {
+	for _, o := range w.outputs {
+		mainText := fmt.Sprintf("%s » %s", o.Name, o.Type)
+		if o.Sensitive {
+			mainText += " 🔒"
+		}
+		list.AddItem(tview.Escape(mainText), "", 0, nil)
+	}
+}
+
+// showOutputValue shows the type and the value of the output, the nested
+// values as YAML and the sensitive ones masked unless revealed. The sensitive
+// values are read when revealed.
+func (w *WorkspacePage) showOutputValue(index int) {
+	if w.outputValue == nil {
+		return
+	}
+	if w.outputsErr != nil {
+		w.outputValue.SetText(fmt.Sprintf("[red]😵 %s[-]", tview.Escape(w.outputsErr.Error())))
+		return
+	}
+	if index < 0 || index >= len(w.outputs) {
+		w.outputValue.SetText("[gray::i]no outputs in the current state[-::-]")
+		return
+	}
+
+	o := w.outputs[index]
+	output := workspaceOutput{
+		Name:      o.Name,
+		Type:      o.Type,
+		Sensitive: o.Sensitive,
+		Value:     o.Value,
+	}
+	if o.Sensitive {
+		value, ok := w.sensitiveValues[o.ID]
+		switch {
+		case !w.revealOutputs:
+			output.Value = sensitiveValue
+		case ok:
+			output.Value = value
+		default:
+			output.Value = "(reading the value)"
+			w.readSensitiveOutput(o, func(value interface{}) {
+				w.showOutputValue(w.outputsList.GetCurrentItem())
+			})
+		}
+	}
+
+	yamlData, _ := yaml.Marshal(output)
+	w.outputValue.SetText(colorizeYAML(string(yamlData)))
+	w.outputValue.ScrollToBeginning()
+}
+
+// readSensitiveOutput reads the value of the sensitive output in the
+// background, then keeps it and calls done on the UI goroutine.
+func (w *WorkspacePage) readSensitiveOutput(o *tfe.StateVersionOutput, done func(value interface{})) {
+	if w.readingOutputs[o.ID] {
+		return
+	}
+	w.readingOutputs[o.ID] = true
+
+	ctx := w.app.pageCtx
+	go func() {
+		output, err := w.tfeClient.ReadWorkspaceOutput(ctx, o.ID)
+
+		w.app.QueueUpdateDraw(func() {
+			delete(w.readingOutputs, o.ID)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				w.app.footer.ShowError(fmt.Sprintf("😵 error reading the output %s: %s", o.Name, err))
+				return
+			}
+
+			w.sensitiveValues[o.ID] = output.Value
+			done(output.Value)
+		})
+	}()
+}
+
+// fmtOutputValue returns the value copied to the clipboard, the strings as
+// they are and the other values as JSON, like terraform output -raw and
+// -json do.
+func fmtOutputValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	content, _ := json.Marshal(value)
+	return string(content)
+}
+
+func (w *WorkspacePage) actionToggleRevealOutputs(ek *tcell.EventKey) *tcell.EventKey {
+	w.revealOutputs = !w.revealOutputs
+	w.showOutputValue(w.outputsList.GetCurrentItem())
+
+	if w.revealOutputs {
+		w.app.footer.Show("🔓 sensitive outputs revealed", tview.Styles.PrimaryTextColor)
+	} else {
+		w.app.footer.Show("🔒 sensitive outputs masked", tview.Styles.PrimaryTextColor)
+	}
+	return nil
+}
+
+func (w *WorkspacePage) actionCopyOutput(ek *tcell.EventKey) *tcell.EventKey {
+	index := w.outputsList.GetCurrentItem()
+	if index < 0 || index >= len(w.outputs) {
+		return nil
+	}
+
+	o := w.outputs[index]
+	if !o.Sensitive {
+		w.copyOutput(o.Name, o.Value)
+		return nil
+	}
+	if value, ok := w.sensitiveValues[o.ID]; ok {
+		w.copyOutput(o.Name, value)
+		return nil
+	}
+
+	w.readSensitiveOutput(o, func(value interface{}) {
+		w.copyOutput(o.Name, value)
+	})
+	return nil
+}
+
+func (w *WorkspacePage) copyOutput(name string, value interface{}) {
+	if err := copyToClipboard(fmtOutputValue(value)); err != nil {
+		w.app.footer.ShowError(fmt.Sprintf("😵 error copying the output: %s", err))
+		return
+	}
+
+	w.app.footer.Show(fmt.Sprintf("📋 %s copied to the clipboard", name), tview.Styles.PrimaryTextColor)
+}
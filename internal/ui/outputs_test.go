@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestFmtOutputValue(t *testing.T) {
+	assert.Equal(t, "acme-logs", fmtOutputValue("acme-logs"))
+	assert.Equal(t, "3", fmtOutputValue(float64(3)))
+	assert.Equal(t, `{"api":"https://api.acme.example.com","ports":[80,443]}`, fmtOutputValue(map[string]interface{}{
+		"api":   "https://api.acme.example.com",
+		"ports": []interface{}{float64(80), float64(443)},
+	}))
+}
+
+func TestWorkspacePageOutputs(t *testing.T) {
+	copied := make(chan string, 2)
+	original := copyToClipboard
+	copyToClipboard = func(text string) error {
+		copied <- text
+		return nil
+	}
+	t.Cleanup(func() {
+		copyToClipboard = original
+	})
+
+	c := newFakeClient()
+	c.AddOutput("ws-prod", fake.NewOutput("db_password", "string", "hunter2", true))
+	c.AddOutput("ws-prod", fake.NewOutput("endpoints", "object", map[string]interface{}{
+		"api":   "https://api.acme.example.com",
+		"ports": []interface{}{float64(80), float64(443)},
+	}, false))
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod", WorkspaceShowVariables: true}
+	h := newHarness(t, cfg, c)
+	h.waitFor("db_password » string 🔒")
+	h.waitFor("endpoints » object")
+	h.waitFor("Value: (sensitive value)")
+
+	h.pressRune('r')
+	h.waitFor("Value: hunter2")
+	h.pressRune('r')
+	h.waitFor("Value: (sensitive value)")
+
+	// details, tags, accesses, variables, runs and then the outputs
+	for i := 0; i < 6; i++ {
+		h.pressKey(tcell.KeyTab)
+	}
+	h.pressRune('c')
+	h.pressKey(tcell.KeyDown)
+	h.waitFor("api: https://api.acme.example.com")
+	h.pressRune('c')
+	h.waitFor("endpoints copied to the clipboard")
+
+	assert.Equal(t, "hunter2", <-copied, "the sensitive values are copied even if masked")
+	assert.Equal(t, `{"api":"https://api.acme.example.com","ports":[80,443]}`, <-copied)
+}
+
+func TestWorkspacePageNoOutputs(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newFakeClient())
+	h.waitFor("no outputs in the current state")
+}
+
+func TestWorkspacePageOutputsError(t *testing.T) {
+	c := newFakeClient()
+	c.FailWith("ListWorkspaceOutputs", errors.New("boom"))
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("app-prod")
+	h.waitFor("error reading the workspace outputs: boom")
+}
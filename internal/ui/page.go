@@ -1,13 +1,19 @@
 package ui
 
-import "github.com/rivo/tview"
+import (
+	"context"
 
-type PageFactory func(*App) Page
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+type PageFactory func(*App, client.TFEClient) Page
 
 type Page interface {
 	tview.Primitive
 
-	Load() error
+	Load(ctx context.Context) error
 	View() string
 	BindKeys() KeyActions
 	Crumb() []string
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const PlanChangesPageName string = "changes"
+
+// planActions are the actions the plan changes can be filtered by.
+var planActions = []string{"create", "update", "replace", "delete", "read", "no-op"}
+
+// PlanChangesPageSource lists the resource changes of the run plan, from the
+// plan JSON output. The search text filters by action, e.g. "create delete",
+// and by address.
+type PlanChangesPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+
+	plan    *client.PlanJSON
+	changes []client.ResourceChange
+}
+
+func NewPlanChangesPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &PlanChangesPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (p *PlanChangesPageSource) SupportsSearch() bool {
+	return true
+}
+
+// Search reads the plan JSON output once, as it does not change, and filters
+// its resource changes.
+func (p *PlanChangesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	if p.plan == nil {
+		run, err := p.tfeClient.ReadWorkspaceRun(ctx, p.app.config.RunID)
+		if err != nil {
+			return fmt.Errorf("error reading the run: %w", err)
+		}
+
+		plan, err := p.tfeClient.ReadWorkspacePlanJSONOutput(ctx, run.Plan.ID)
+		if err != nil {
+			return fmt.Errorf("error reading the plan JSON output: %w", err)
+		}
+		p.plan = plan
+	}
+
+	p.changes = filterPlanChanges(p.plan.ResourceChanges, searchText)
+
+	return nil
+}
+
+// filterPlanChanges returns the changes with any of the actions in the search
+// text and an address containing all the other words.
+func filterPlanChanges(changes []client.ResourceChange, searchText string) []client.ResourceChange {
+	actions := map[string]bool{}
+	words := []string{}
+	for _, w := range strings.Fields(searchText) {
+		if contains(planActions, w) {
+			actions[w] = true
+		} else {
+			words = append(words, w)
+		}
+	}
+
+	filtered := []client.ResourceChange{}
+	for _, c := range changes {
+		if len(actions) > 0 && !actions[c.Change.Action()] {
+			continue
+		}
+
+		matches := true
+		for _, w := range words {
+			if !strings.Contains(c.Address, w) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func (p *PlanChangesPageSource) BindKeys(l *ListPage) KeyActions {
+	return KeyActions{
+		KeyO: NewKeyAction("sort by address", func(ek *tcell.EventKey) *tcell.EventKey {
+			l.SortBy(1)
+			return nil
+		}, true),
+	}
+}
+
+func (p *PlanChangesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "ACTION"},
+		{Name: "ADDRESS", Sorted: true},
+		{Name: "PROVIDER"},
+		{Name: "MODULE"},
+	}
+}
+
+func (p *PlanChangesPageSource) RenderRows(table *tview.Table) {
+	for i, c := range p.changes {
+		r := i + 1
+		table.SetCell(r, 0, tview.NewTableCell(fmtPlanAction(c.Change.Action())).SetExpansion(1).SetReference(&p.changes[i]))
+		table.SetCell(r, 1, tview.NewTableCell(c.Address).SetExpansion(3))
+		table.SetCell(r, 2, tview.NewTableCell(strings.TrimPrefix(c.ProviderName, "registry.terraform.io/")).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(c.ModuleAddress).SetExpansion(1))
+	}
+}
+
+// fmtPlanAction returns the action with its icon and color, like in the
+// structured logs.
+func fmtPlanAction(action string) string {
+	a, ok := logActions[action]
+	if !ok {
+		return action
+	}
+	return fmt.Sprintf("[%s]%s %s[-]", a[1], a[0], action)
+}
+
+func (p *PlanChangesPageSource) Crumb() []string {
+	return []string{
+		p.app.config.Organization,
+		p.app.config.Workspace,
+		"runs",
+		p.app.config.RunID,
+		PlanChangesPageName,
+	}
+}
+
+func (p *PlanChangesPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		change, ok := table.GetCell(currentItem, 0).GetReference().(*client.ResourceChange)
+		if !ok {
+			return nil
+		}
+
+		p.app.activatePage(PlanChangePageName, NewPlanChangePage(p.app, *change), false)
+		return nil
+	}
+}
+
+func (p *PlanChangesPageSource) Name() string {
+	return "change"
+}
+
+func (p *PlanChangesPageSource) NameList() string {
+	return PlanChangesPageName
+}
+
+func (p *PlanChangesPageSource) Empty() bool {
+	return len(p.changes) == 0
+}
+
+func (p *PlanChangesPageSource) CurrentPage() int {
+	return 1
+}
+
+func (p *PlanChangesPageSource) TotalCount() int {
+	return len(p.changes)
+}
+
+func (p *PlanChangesPageSource) TotalPages() int {
+	return 1
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
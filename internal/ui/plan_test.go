@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func newPlanJSON() *client.PlanJSON {
+	return &client.PlanJSON{
+		TerraformVersion: "1.1.9",
+		ResourceChanges: []client.ResourceChange{
+			{
+				Address:      "aws_s3_bucket.logs",
+				ProviderName: "registry.terraform.io/hashicorp/aws",
+				Change: client.Change{
+					Actions: []string{"create"},
+					After:   map[string]interface{}{"bucket": "acme-logs"},
+				},
+			},
+			{
+				Address:       "module.db.aws_db_instance.main",
+				ModuleAddress: "module.db",
+				ProviderName:  "registry.terraform.io/hashicorp/aws",
+				ActionReason:  "replace_because_cannot_update",
+				Change: client.Change{
+					Actions:         []string{"delete", "create"},
+					Before:          map[string]interface{}{"engine": "postgres", "password": "hunter2"},
+					After:           map[string]interface{}{"engine": "postgres", "password": "hunter3"},
+					BeforeSensitive: map[string]interface{}{"password": true},
+					AfterSensitive:  map[string]interface{}{"password": true},
+				},
+			},
+			{
+				Address:      "aws_iam_role.app",
+				ProviderName: "registry.terraform.io/hashicorp/aws",
+				Change:       client.Change{Actions: []string{"no-op"}},
+			},
+		},
+	}
+}
+
+func TestFilterPlanChanges(t *testing.T) {
+	testCases := []struct {
+		name       string
+		searchText string
+		expected   []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"aws_s3_bucket.logs", "module.db.aws_db_instance.main", "aws_iam_role.app"},
+		},
+		{
+			name:       "action",
+			searchText: "replace",
+			expected:   []string{"module.db.aws_db_instance.main"},
+		},
+		{
+			name:       "actions",
+			searchText: "create no-op",
+			expected:   []string{"aws_s3_bucket.logs", "aws_iam_role.app"},
+		},
+		{
+			name:       "address",
+			searchText: "aws_",
+			expected:   []string{"aws_s3_bucket.logs", "module.db.aws_db_instance.main", "aws_iam_role.app"},
+		},
+		{
+			name:       "action and address",
+			searchText: "create no-op iam",
+			expected:   []string{"aws_iam_role.app"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addresses := []string{}
+			for _, c := range filterPlanChanges(newPlanJSON().ResourceChanges, tc.searchText) {
+				addresses = append(addresses, c.Address)
+			}
+			assert.Equal(t, tc.expected, addresses)
+		})
+	}
+}
+
+func TestMaskSensitive(t *testing.T) {
+	value := map[string]interface{}{
+		"name":  "db",
+		"creds": map[string]interface{}{"user": "admin", "password": "hunter2"},
+		"keys":  []interface{}{"a", "b"},
+	}
+	sensitive := map[string]interface{}{
+		"creds": map[string]interface{}{"password": true},
+		"keys":  []interface{}{false, true},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":  "db",
+		"creds": map[string]interface{}{"user": "admin", "password": sensitiveValue},
+		"keys":  []interface{}{"a", sensitiveValue},
+	}, maskSensitive(value, sensitive))
+	assert.Equal(t, sensitiveValue, maskSensitive(value, true))
+	assert.Equal(t, value, maskSensitive(value, false))
+	assert.Nil(t, maskSensitive(nil, map[string]interface{}{}))
+}
+
+func TestPlanChangesPage(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanJSON("plan-run-new", newPlanJSON())
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showRun(h, "run-new")
+
+	h.pressRune('p')
+	h.waitFor("<changes>")
+	h.waitFor("ADDRESS ▲")
+	h.waitFor("hashicorp/aws")
+	h.waitFor("module.db")
+	text := h.text()
+	assert.Less(t, strings.Index(text, "aws_iam_role.app"), strings.Index(text, "aws_s3_bucket.logs"))
+
+	h.pressRune('o')
+	h.waitFor("ADDRESS ▼")
+	text = h.text()
+	assert.Greater(t, strings.Index(text, "aws_iam_role.app"), strings.Index(text, "aws_s3_bucket.logs"))
+
+	h.pressRune('/')
+	h.typeText("replace")
+	h.pressKey(tcell.KeyEnter)
+	h.waitForGone("aws_s3_bucket.logs")
+	h.waitFor("-/+ replace")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<module.db.aws_db_instance.main>")
+	h.waitFor("Reason: replace_because_cannot_update")
+	h.waitFor("password: (sensitive value)")
+	assert.NotContains(t, h.text(), "hunter")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("ADDRESS ▼")
+	h.waitForGone("Reason:")
+}
+
+func TestPlanChangesPageGoesUpToRun(t *testing.T) {
+	c := newFakeClient()
+	c.SetPlanJSON("plan-run-new", newPlanJSON())
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod", RunID: "run-new"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+	h.app.QueueUpdate(func() {
+		h.app.activatePage(PlanChangesPageName, nil, false)
+	})
+	h.waitFor("<changes>")
+	h.app.QueueUpdate(func() {
+		h.app.history.Clear()
+	})
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("run details")
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+const ProfilesPageName string = "profiles"
+
+type ProfilesPageSource struct {
+	app      *App
+	profiles []*config.Profile
+}
+
+func NewProfilesPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &ProfilesPageSource{app: app})
+}
+
+func (p *ProfilesPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (p *ProfilesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	p.profiles = p.app.config.Profiles
+	return nil
+}
+
+func (p *ProfilesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "NAME"},
+		{Name: "HOSTNAME"},
+		{Name: "ORGANIZATION"},
+		{Name: "TOKEN"},
+		{Name: "ACTIVE"},
+	}
+}
+
+func (p *ProfilesPageSource) RenderRows(table *tview.Table) {
+	for i, profile := range p.profiles {
+		r := i + 1
+
+		hostname := profile.Hostname
+		if hostname == "" {
+			hostname = client.DefaultHostname
+		}
+
+		token := "terraform CLI credentials"
+		if profile.TokenEnv != "" {
+			token = fmt.Sprintf("$%s", profile.TokenEnv)
+		}
+
+		active := ""
+		if profile.Name == p.app.config.CurrentProfile {
+			active = "✅"
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(profile.Name).SetExpansion(1))
+		table.SetCell(r, 1, tview.NewTableCell(hostname).SetExpansion(2))
+		table.SetCell(r, 2, tview.NewTableCell(profile.Organization).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(token).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(active).SetExpansion(1))
+	}
+}
+
+func (p *ProfilesPageSource) Crumb() []string {
+	return []string{ProfilesPageName}
+}
+
+func (p *ProfilesPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		p.app.switchProfile(table.GetCell(currentItem, 0).Text)
+		return nil
+	}
+}
+
+func (p *ProfilesPageSource) Name() string {
+	return "profile"
+}
+
+func (p *ProfilesPageSource) NameList() string {
+	return ProfilesPageName
+}
+
+func (p *ProfilesPageSource) Empty() bool {
+	return len(p.profiles) == 0
+}
+
+func (p *ProfilesPageSource) CurrentPage() int {
+	return 1
+}
+
+func (p *ProfilesPageSource) TotalCount() int {
+	return len(p.profiles)
+}
+
+func (p *ProfilesPageSource) TotalPages() int {
+	return 1
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+)
+
+const defaultRefreshInterval = 5 * time.Second
+
+// RefreshablePage is a page updated in place by the auto refresh while it
+// shows something still changing, like a run in progress.
+type RefreshablePage interface {
+	Page
+
+	// RefreshFunc returns the function fetching the latest page data. It is
+	// called on the UI goroutine and the returned function in the background.
+	RefreshFunc() func(context.Context) error
+	// Repaint updates the rendered page with the refreshed data, keeping the
+	// selection and scroll position.
+	Repaint()
+	// Settled is true when nothing on the page is expected to change.
+	Settled() bool
+}
+
+// runSettled returns true if the run reached a final state, or waits for
+// the user to confirm it, override its policies or for the runs queued before
+// it, unless it is applied automatically.
+func runSettled(run *tfe.Run) bool {
+	if run == nil {
+		return true
+	}
+
+	switch run.Status {
+	case tfe.RunApplied,
+		tfe.RunPlannedAndFinished,
+		tfe.RunErrored,
+		tfe.RunDiscarded,
+		tfe.RunCanceled,
+		tfe.RunPending,
+		tfe.RunPolicyOverride,
+		tfe.RunPolicySoftFailed:
+		return true
+	case tfe.RunPlanned,
+		tfe.RunPolicyChecked,
+		tfe.RunCostEstimated:
+		return !run.AutoApply
+	}
+	return false
+}
+
+func (a *App) refreshInterval() time.Duration {
+	if a.config.RefreshInterval <= 0 {
+		return defaultRefreshInterval
+	}
+	return time.Duration(a.config.RefreshInterval) * time.Second
+}
+
+// startAutoRefresh refreshes the current page on every refresh interval. It
+// returns a function to stop refreshing.
+func (a *App) startAutoRefresh() func() {
+	ticker := time.NewTicker(a.refreshInterval())
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				a.QueueUpdate(a.refreshPage)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// refreshPage refreshes the current page in the background if auto refresh is
+// on and the page is not settled, skipping it while a refresh is running.
+func (a *App) refreshPage() {
+	if !a.config.AutoRefresh || a.refreshing {
+		return
+	}
+	if a.current == nil || !a.current.rendered {
+		return
+	}
+
+	page, ok := a.current.page.(RefreshablePage)
+	if !ok || page.Settled() {
+		return
+	}
+
+	a.reloadPage(page)
+}
+
+// reloadPage refreshes the page in the background and repaints it, e.g. after
+// an action changed what it shows. While a refresh is running the reload is
+// queued until it is done, as both would load the page at the same time.
+func (a *App) reloadPage(page RefreshablePage) {
+	ctx := a.pageCtx
+	if a.refreshing {
+		a.queuedReload = func() {
+			if ctx.Err() == nil {
+				a.reloadPage(page)
+			}
+		}
+		return
+	}
+
+	a.refreshing = true
+	refreshFn := page.RefreshFunc()
+
+	go func() {
+		err := refreshFn(ctx)
+
+		a.QueueUpdateDraw(func() {
+			a.refreshing = false
+			if reload := a.queuedReload; reload != nil {
+				a.queuedReload = nil
+				defer reload()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				a.footer.ShowError(fmt.Sprintf("😵 %s", err.Error()))
+				return
+			}
+
+			page.Repaint()
+		})
+	}()
+}
+
+func (a *App) toggleAutoRefresh(ek *tcell.EventKey) *tcell.EventKey {
+	a.config.AutoRefresh = !a.config.AutoRefresh
+	a.config.Save()
+
+	if a.config.AutoRefresh {
+		a.footer.Show(fmt.Sprintf("🔄 auto refresh on, every %s", a.refreshInterval()), tview.Styles.SecondaryTextColor)
+	} else {
+		a.footer.Show("⏸ auto refresh off", tview.Styles.SecondaryTextColor)
+	}
+	return nil
+}
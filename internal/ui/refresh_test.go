@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestRunSettled(t *testing.T) {
+	assert.True(t, runSettled(nil))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunApplied}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunErrored}))
+	assert.False(t, runSettled(&tfe.Run{Status: tfe.RunPlanning}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunPlanned}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunPolicyChecked}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunCostEstimated}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunPolicyOverride}))
+	assert.True(t, runSettled(&tfe.Run{Status: tfe.RunPending}))
+	assert.False(t, runSettled(&tfe.Run{Status: tfe.RunPlanned, AutoApply: true}))
+	assert.False(t, runSettled(&tfe.Run{Status: tfe.RunApplying}))
+}
+
+func newRefreshConfig() *config.Config {
+	return &config.Config{
+		Organization:           "acme",
+		WorkspaceShowVariables: true,
+		AutoRefresh:            true,
+		RefreshInterval:        1,
+	}
+}
+
+func TestAutoRefreshWorkspaces(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-dev", fake.NewRun("run-live", "scale up", tfe.RunPlanning))
+
+	h := newHarness(t, newRefreshConfig(), c)
+	h.waitFor("planning")
+
+	c.SetRunStatus("run-live", tfe.RunApplied)
+	h.waitForGone("planning")
+	h.waitFor("applied")
+}
+
+func TestAutoRefreshWorkspaceKeepsSelection(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-live", "scale up", tfe.RunApplying))
+
+	cfg := newRefreshConfig()
+	cfg.Workspace = "app-prod"
+	h := newHarness(t, cfg, c)
+	h.waitFor("scale up » ⏳ applying")
+
+	// details, tags, accesses, variables and then the runs
+	for i := 0; i < 5; i++ {
+		h.pressKey(tcell.KeyTab)
+	}
+	h.pressKey(tcell.KeyDown)
+	h.waitUntil(func() bool {
+		selected := ""
+		h.onUI(func() {
+			selected = h.app.current.page.(*WorkspacePage).selectedRunID
+		})
+		return selected == "run-new"
+	}, "run not selected")
+
+	c.SetRunStatus("run-live", tfe.RunApplied)
+	h.waitFor("scale up » ✅")
+	h.waitFor("Status: applied")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run details")
+	assert.Equal(t, "run-new", h.config.RunID)
+}
+
+func TestAutoRefreshWorkspacePanels(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-live", "scale up", tfe.RunApplying))
+
+	cfg := newRefreshConfig()
+	cfg.Workspace = "app-prod"
+	h := newHarness(t, cfg, c)
+	h.waitFor("scale up » ⏳ applying")
+
+	c.AddVariable("ws-prod", fake.NewVariable("var-3", "instance_type", "t3.micro"))
+	c.AddTeamAccess("ws-prod", fake.NewTeamAccess("developers", tfe.AccessWrite))
+	h.waitFor(`instance_type = t3.micro`)
+	h.waitFor("developers")
+}
+
+func TestAutoRefreshToggle(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-dev", fake.NewRun("run-live", "scale up", tfe.RunPlanning))
+
+	h := newHarness(t, newRefreshConfig(), c)
+	h.waitFor("planning")
+
+	h.pressKey(tcell.KeyCtrlR)
+	h.waitFor("auto refresh off")
+	assert.False(t, h.config.AutoRefresh)
+
+	h.pressKey(tcell.KeyCtrlR)
+	h.waitFor("auto refresh on")
+	assert.True(t, h.config.AutoRefresh)
+}
+
+func TestAutoRefreshRun(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-live", "scale up", tfe.RunPlanning))
+
+	cfg := newRefreshConfig()
+	cfg.Workspace = "app-prod"
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+
+	h.pressRune(':')
+	h.typeText("run run-live")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run details")
+
+	// the status is below the visible part of the details
+	details := func() string {
+		text := ""
+		h.onUI(func() {
+			text = h.app.current.page.(*RunPage).details.GetText(true)
+		})
+		return text
+	}
+	assert.Contains(t, details(), "Status: planning")
+
+	c.SetRunStatus("run-live", tfe.RunPlanned)
+	h.waitUntil(func() bool {
+		return strings.Contains(details(), "Status: planned\n")
+	}, "run status not refreshed")
+}
+
+func TestReloadPageWhileRefreshing(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	h.waitFor("workspace details")
+
+	queued := false
+	h.onUI(func() {
+		page := h.app.current.page.(RefreshablePage)
+		h.app.reloadPage(page)
+		h.app.reloadPage(page)
+		queued = h.app.queuedReload != nil
+	})
+	assert.True(t, queued)
+
+	h.waitUntil(func() bool {
+		done := false
+		h.onUI(func() {
+			done = !h.app.refreshing && h.app.queuedReload == nil
+		})
+		return done
+	}, "the queued reload did not run")
+}
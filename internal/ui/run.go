@@ -1,9 +1,7 @@
 package ui
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 
@@ -19,19 +17,23 @@ const RunPageName string = "Run"
 type RunPage struct {
 	*tview.Flex
 
+	details        *tview.TextView
 	planPrimitive  *tview.TextView
 	applyPrimitive *tview.TextView
 
-	app *App
+	app       *App
+	tfeClient client.TFEClient
 
 	run   *tfe.Run
 	plan  *tfe.Plan
 	apply *tfe.Apply
+	// refreshed is the run read by the auto refresh, shown by Repaint
+	refreshed *tfe.Run
 
-	planReader  io.Reader
-	applyReader io.Reader
-
-	loadingChan chan string
+	planLogs  *logStream
+	applyLogs *logStream
+	follow    bool
+	raw       bool
 
 	sections []*tview.Box
 }
@@ -47,14 +49,16 @@ type runBaseInfo struct {
 }
 
 type applyLogEntry struct {
-	Level            string         `json:"@level"`
-	Message          string         `json:"@message"`
-	Module           string         `json:"@module"`
-	Change           applyLogChange `json:"change"`
-	Timestamp        string         `json:"@timestamp"`
-	TerraformVersion string         `json:"@terraform"`
-	Type             string         `json:"type"`
-	UI               string         `json:"@ui"`
+	Level            string             `json:"@level"`
+	Message          string             `json:"@message"`
+	Module           string             `json:"@module"`
+	Change           applyLogChange     `json:"change"`
+	Hook             applyLogHook       `json:"hook"`
+	Diagnostic       applyLogDiagnostic `json:"diagnostic"`
+	Timestamp        string             `json:"@timestamp"`
+	TerraformVersion string             `json:"@terraform"`
+	Type             string             `json:"type"`
+	UI               string             `json:"@ui"`
 }
 
 type applyLogChange struct {
@@ -62,6 +66,21 @@ type applyLogChange struct {
 	Action   string                 `json:"action"`
 }
 
+type applyLogHook struct {
+	Resource       applyLogChangeResource `json:"resource"`
+	Action         string                 `json:"action"`
+	IDKey          string                 `json:"id_key"`
+	IDValue        string                 `json:"id_value"`
+	ElapsedSeconds float64                `json:"elapsed_seconds"`
+}
+
+type applyLogDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Address  string `json:"address"`
+}
+
 type applyLogChangeResource struct {
 	Addr            string `json:"addr"`
 	Module          string `json:"module"`
@@ -72,82 +91,36 @@ type applyLogChangeResource struct {
 	ResourceKey     string `json:"resource_key"`
 }
 
-func NewRunPage(app *App) Page {
+func NewRunPage(app *App, tfeClient client.TFEClient) Page {
 	r := RunPage{
-		Flex: tview.NewFlex(),
-		app:  app,
+		Flex:      tview.NewFlex(),
+		app:       app,
+		tfeClient: tfeClient,
+		follow:    true,
 	}
 
 	return &r
 }
 
-func (r *RunPage) Load() error {
-	r.loadingChan = make(chan string)
-	tfeClient, err := client.NewTFEClient()
-	if err != nil {
-		return fmt.Errorf("error creating the TFE client: %w", err)
-	}
-
-	run, err := tfeClient.ReadWorkspaceRun(r.app.config.RunID)
+func (r *RunPage) Load(ctx context.Context) error {
+	run, err := r.tfeClient.ReadWorkspaceRun(ctx, r.app.config.RunID)
 	if err != nil {
 		return fmt.Errorf("error reading the run: %w", err)
 	}
 	r.run = run
 
-	plan, err := tfeClient.ReadWorkspacePlan(run.Plan.ID)
+	plan, err := r.tfeClient.ReadWorkspacePlan(ctx, run.Plan.ID)
 	if err != nil {
 		return fmt.Errorf("error reading the plan: %w", err)
 	}
 	r.plan = plan
 
-	errLoadPlan, errLoadApply := r.loadPlanAndApplyDetails(tfeClient, run)
-
-	if errLoadPlan != nil || errLoadApply != nil {
-		return fmt.Errorf("could not load run details")
-	}
-
 	return nil
 }
 
-func (r *RunPage) loadPlanAndApplyDetails(tfeClient client.TFEClient, run *tfe.Run) (error, error) {
-	var errLoadPlan error
-	go func() {
-		planLogsReader, err := tfeClient.ReadWorkspacePlanLogs(run.Plan.ID)
-		if err != nil {
-			errLoadPlan = fmt.Errorf("error reading the plan details: %w", err)
-		} else {
-			r.planReader = planLogsReader
-		}
-		r.loadingChan <- "plan"
-	}()
-
-	var errLoadApply error
-	go func() {
-		applyLogsReader, err := tfeClient.ReadWorkspaceApplyLogs(run.Apply.ID)
-		if err != nil {
-			errLoadApply = fmt.Errorf("error reading the apply details: %w", err)
-		} else {
-			r.applyReader = applyLogsReader
-		}
-		r.loadingChan <- "apply"
-	}()
-	return errLoadPlan, errLoadApply
-}
-
 func (r *RunPage) View() string {
 	r.sections = []*tview.Box{}
 
-	runBase := runBaseInfo{
-		ID:        r.run.ID,
-		Message:   r.run.Message,
-		Source:    string(r.run.Source),
-		Status:    string(r.run.Status),
-		AutoApply: r.run.AutoApply,
-		IsDestroy: r.run.IsDestroy,
-		CreatedAt: fmtTime(r.run.CreatedAt),
-	}
-	yamlBaseData, _ := yaml.Marshal(runBase)
-
 	details := tview.NewTextView()
 	plan := tview.NewTextView()
 	apply := tview.NewTextView()
@@ -155,13 +128,12 @@ func (r *RunPage) View() string {
 	details.SetBorder(true)
 	details.SetBorderPadding(0, 1, 1, 1)
 	details.SetTitle(" run details ")
-	details.SetText(colorizeYAML(string(yamlBaseData)))
+	details.SetText(r.detailsText())
 	details.SetDynamicColors(true)
 
 	plan.SetBorder(true)
 	plan.SetBorderPadding(0, 1, 1, 1)
 	plan.SetTitle(" plan ")
-	plan.SetText("⏳ loading...")
 	plan.SetDynamicColors(true)
 	plan.SetWrap(false)
 	r.sections = append(r.sections, plan.Box)
@@ -169,7 +141,6 @@ func (r *RunPage) View() string {
 	apply.SetBorder(true)
 	apply.SetBorderPadding(0, 1, 1, 1)
 	apply.SetTitle(" apply ")
-	apply.SetText("⏳ loading...")
 	apply.SetDynamicColors(true)
 	apply.SetWrap(false)
 	r.sections = append(r.sections, apply.Box)
@@ -183,86 +154,103 @@ func (r *RunPage) View() string {
 			AddItem(apply, 0, 1, false), 0, 5, false)
 
 	r.Flex = flex
+	r.details = details
 	r.planPrimitive = plan
 	r.applyPrimitive = apply
 
-	go r.viewPlanAndApplyDetails()
+	r.planLogs = newLogStream(r.app, "plan", plan, func(ctx context.Context) (io.Reader, error) {
+		return r.tfeClient.ReadWorkspacePlanLogs(ctx, r.run.Plan.ID)
+	})
+	r.applyLogs = newLogStream(r.app, "apply", apply, func(ctx context.Context) (io.Reader, error) {
+		return r.tfeClient.ReadWorkspaceApplyLogs(ctx, r.run.Apply.ID)
+	})
+	r.planLogs.SetFollow(r.follow)
+	r.applyLogs.SetFollow(r.follow)
+	r.planLogs.SetRaw(r.raw)
+	r.applyLogs.SetRaw(r.raw)
+	r.planLogs.Start(r.app.pageCtx)
+	r.applyLogs.Start(r.app.pageCtx)
 
 	return "run loaded"
 }
 
-func (r *RunPage) viewPlanAndApplyDetails() {
-	loadingAsyncsFinished := 0
-	for v := range r.loadingChan {
-		if v == "plan" {
-			loadingAsyncsFinished++
-			if r.planReader == nil {
-				r.planPrimitive.SetText("plan details could not be loaded!")
-				continue
-			}
-			go r.viewPlanDetails()
-		} else if v == "apply" {
-			loadingAsyncsFinished++
-			if r.applyReader == nil {
-				r.applyPrimitive.SetText("apply details could not be loaded!")
-				continue
-			}
-			go r.viewApplyDetails()
-		}
-
-		if loadingAsyncsFinished == 2 {
-			break
-		}
+func (r *RunPage) detailsText() string {
+	runBase := runBaseInfo{
+		ID:        r.run.ID,
+		Message:   r.run.Message,
+		Source:    string(r.run.Source),
+		Status:    string(r.run.Status),
+		AutoApply: r.run.AutoApply,
+		IsDestroy: r.run.IsDestroy,
+		CreatedAt: fmtTime(r.run.CreatedAt),
 	}
-}
-
-func (r *RunPage) viewPlanDetails() {
-	scanner := bufio.NewScanner(r.planReader)
-	scanner.Split(bufio.ScanLines)
+	yamlBaseData, _ := yaml.Marshal(runBase)
 
-	outputBuf := bytes.Buffer{}
-	for scanner.Scan() {
-		log := applyLogEntry{}
-		err := json.Unmarshal(scanner.Bytes(), &log)
+	return colorizeYAML(string(yamlBaseData))
+}
 
+func (r *RunPage) RefreshFunc() func(context.Context) error {
+	runID := r.run.ID
+	return func(ctx context.Context) error {
+		run, err := r.tfeClient.ReadWorkspaceRun(ctx, runID)
 		if err != nil {
-			outputBuf.WriteString(fmt.Sprintln(scanner.Text()))
-			continue
+			return fmt.Errorf("error reading the run: %w", err)
 		}
+		r.refreshed = run
 
-		outputBuf.WriteString(fmt.Sprintln(log.Message))
+		return nil
 	}
-	r.planPrimitive.SetText(outputBuf.String())
 }
 
-func (r *RunPage) viewApplyDetails() {
-	scanner := bufio.NewScanner(r.applyReader)
-	scanner.Split(bufio.ScanLines)
-
-	outputBuf := bytes.Buffer{}
-	for scanner.Scan() {
-		log := applyLogEntry{}
-		err := json.Unmarshal(scanner.Bytes(), &log)
+// Repaint updates the run details and restarts the log streams stopped
+// before the end, e.g. when leaving the page while they were running.
+func (r *RunPage) Repaint() {
+	if r.refreshed != nil {
+		r.run = r.refreshed
+		r.refreshed = nil
+	}
+	r.details.SetText(r.detailsText())
 
-		if err != nil {
-			outputBuf.WriteString(fmt.Sprintln(scanner.Text()))
-			continue
+	for _, s := range []*logStream{r.planLogs, r.applyLogs} {
+		if s.Stopped() {
+			s.Start(r.app.pageCtx)
 		}
-
-		outputBuf.WriteString(fmt.Sprintln(log.Message))
 	}
-	r.applyPrimitive.SetText(outputBuf.String())
+}
+
+func (r *RunPage) Settled() bool {
+	return runSettled(r.run) && r.planLogs.Settled() && r.applyLogs.Settled()
 }
 
 func (r *RunPage) BindKeys() KeyActions {
-	return KeyActions{
-		tcell.KeyCtrlW: NewKeyAction("go back to the workspace", r.actionReturnToWorkspace, true),
-		tcell.KeyTab:   NewKeyAction("focus plan and apply cells", r.actionFocusNextList, true),
+	aa := KeyActions{
+		tcell.KeyTab: NewKeyAction("focus plan and apply cells", r.actionFocusNextList, true),
+		KeyF:         NewKeyAction("follow the logs", r.actionToggleFollow, true),
+		KeyS:         NewKeyAction("structured or raw logs", r.actionToggleRaw, true),
+		KeyP:         NewKeyAction("plan changes", r.actionShowPlanChanges, true),
 	}
+	aa.Add(r.bindRunActions())
+
+	return aa
+}
+
+func (r *RunPage) actionShowPlanChanges(ek *tcell.EventKey) *tcell.EventKey {
+	r.app.activatePage(PlanChangesPageName, nil, false)
+	return nil
+}
+
+func (r *RunPage) actionToggleRaw(ek *tcell.EventKey) *tcell.EventKey {
+	r.raw = !r.raw
+	r.planLogs.SetRaw(r.raw)
+	r.applyLogs.SetRaw(r.raw)
+
+	return nil
 }
 
-func (r *RunPage) actionReturnToWorkspace(ek *tcell.EventKey) *tcell.EventKey {
-	r.app.activatePage(WorkspacePageName, nil, false)
+func (r *RunPage) actionToggleFollow(ek *tcell.EventKey) *tcell.EventKey {
+	r.follow = !r.follow
+	r.planLogs.SetFollow(r.follow)
+	r.applyLogs.SetFollow(r.follow)
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func showRun(h *harness, runID string) {
+	h.app.QueueUpdate(func() {
+		h.config.RunID = runID
+		h.app.activatePage(RunPageName, nil, false)
+	})
+	h.waitFor("run details")
+}
+
+func TestRunPage(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newFakeClient())
+	showRun(h, "run-new")
+
+	h.waitFor("Message: add bucket")
+	h.waitFor("Terraform v1.1.9")
+	h.waitFor("Plan: 1 to add, 0 to change, 0 to destroy.")
+	h.waitFor("Error: creating bucket")
+	h.waitFor("plan ✅ 3 lines | follow")
+	h.waitFor("apply ✅ 1 lines | follow")
+}
+
+func TestRunPageStreamsLogs(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-live", "scale up", tfe.RunApplying))
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	c.SetLogsReader("apply-run-live", reader)
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showRun(h, "run-live")
+	h.waitFor("apply ⏳ 0 lines")
+
+	// lines are shown as they arrive, the partial line once completed
+	io.WriteString(writer, "aws_instance.web: Creating...\naws_instance.web: Still ")
+	h.waitFor("aws_instance.web: Creating...")
+	h.waitFor("apply ⏳ 1 lines")
+
+	io.WriteString(writer, "creating... [10s elapsed]\n")
+	h.waitFor("aws_instance.web: Still creating... [10s elapsed]")
+	h.waitFor("apply ⏳ 2 lines")
+
+	writer.Close()
+	h.waitFor("apply ✅ 2 lines")
+}
+
+func TestRunPageFollow(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newFakeClient())
+	showRun(h, "run-new")
+	h.waitFor("plan ✅ 3 lines | follow")
+
+	h.pressRune('f')
+	h.waitFor("plan ✅ 3 lines ")
+	h.waitForGone("| follow")
+
+	h.pressRune('f')
+	h.waitFor("plan ✅ 3 lines | follow")
+}
+
+func TestRunPageLogsError(t *testing.T) {
+	c := newFakeClient()
+	c.FailWith("ReadWorkspaceApplyLogs", errors.New("apply has no logs"))
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showRun(h, "run-new")
+
+	h.waitFor("apply details could not be loaded: apply has no logs")
+	h.waitFor("apply ❌")
+	h.waitFor("plan ✅")
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+// queuedRunMessage is the message of the runs queued from terrui.
+const queuedRunMessage = "Queued from terrui"
+
+// runAction is an action changing a run, confirmed by typing the workspace
+// name.
+type runAction struct {
+	verb    string
+	done    string
+	allowed func(*tfe.RunActions) bool
+	do      func(ctx context.Context, runID string) error
+}
+
+func (r *RunPage) runActions() map[tcell.Key]runAction {
+	return map[tcell.Key]runAction{
+		KeyA: {
+			verb:    "apply",
+			done:    "apply confirmed",
+			allowed: func(a *tfe.RunActions) bool { return a.IsConfirmable },
+			do:      r.tfeClient.ApplyRun,
+		},
+		KeyD: {
+			verb:    "discard",
+			done:    "discarded",
+			allowed: func(a *tfe.RunActions) bool { return a.IsDiscardable },
+			do:      r.tfeClient.DiscardRun,
+		},
+		KeyX: {
+			verb:    "cancel",
+			done:    "cancel requested",
+			allowed: func(a *tfe.RunActions) bool { return a.IsCancelable },
+			do:      r.tfeClient.CancelRun,
+		},
+		KeyShiftX: {
+			verb:    "force cancel",
+			done:    "force canceled",
+			allowed: func(a *tfe.RunActions) bool { return a.IsForceCancelable },
+			do:      r.tfeClient.ForceCancelRun,
+		},
+	}
+}
+
+func (r *RunPage) bindRunActions() KeyActions {
+	aa := KeyActions{}
+	for key, action := range r.runActions() {
+		aa[key] = NewKeyAction(fmt.Sprintf("%s run", action.verb), r.actionRun(action), true)
+	}
+	return aa
+}
+
+// actionRun asks to confirm the action and then requests it, refreshing the
+// page once done.
+func (r *RunPage) actionRun(action runAction) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		run := r.run
+		if run == nil {
+			return nil
+		}
+		if run.Actions == nil || !action.allowed(run.Actions) {
+			r.app.footer.ShowError(fmt.Sprintf("😵 cannot %s the run while %s", action.verb, run.Status))
+			return nil
+		}
+
+		// the name to type is the one of the run workspace, whatever the
+		// workspace selected
+		if run.Workspace == nil || run.Workspace.Name == "" {
+			r.app.footer.ShowError(fmt.Sprintf("😵 cannot %s the run, its workspace is unknown", action.verb))
+			return nil
+		}
+		workspace := run.Workspace.Name
+
+		text := fmt.Sprintf("%s the run [::b]%s[::-] of the workspace [::b]%s[::-]?",
+			capitalize(action.verb), run.ID, tview.Escape(workspace))
+		r.app.confirm(fmt.Sprintf("%s run", action.verb), text, workspace, func() {
+			ctx := r.app.pageCtx
+			go func() {
+				err := action.do(ctx, run.ID)
+
+				r.app.QueueUpdateDraw(func() {
+					if err != nil {
+						r.app.footer.ShowError(fmt.Sprintf("😵 error trying to %s the run: %s", action.verb, err))
+						return
+					}
+
+					r.app.footer.Show(fmt.Sprintf("✅ %s: %s", run.ID, action.done), tview.Styles.SecondaryTextColor)
+					if ctx.Err() == nil {
+						r.app.reloadPage(r)
+					}
+				})
+			}()
+		})
+		return nil
+	}
+}
+
+// actionQueueRun asks to confirm and queues a run in the workspace.
+func (w *WorkspacePage) actionQueueRun(options client.RunOptions) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		if w.workspace == nil {
+			return nil
+		}
+
+		w.app.confirmQueueRun(w.workspace, options)
+		return nil
+	}
+}
+
+// runKind returns the kind of the run queued with the options.
+func runKind(options client.RunOptions) string {
+	switch {
+	case options.IsDestroy:
+		return "destroy"
+	case options.RefreshOnly:
+		return "refresh only"
+	case options.PlanOnly:
+		return "plan only"
+	}
+	return "plan and apply"
+}
+
+// confirmQueueRun asks to confirm and queues a run in the workspace, opening
+// it once queued. The destroy runs, and the runs applied automatically,
+// require typing the workspace name.
+func (a *App) confirmQueueRun(workspace *tfe.Workspace, options client.RunOptions) {
+	kind := runKind(options)
+	text := fmt.Sprintf("Queue a [::b]%s[::-] run in the workspace [::b]%s[::-]?", kind, tview.Escape(workspace.Name))
+
+	expected := ""
+	autoApply := workspace.AutoApply && !options.PlanOnly
+	if options.IsDestroy || autoApply {
+		expected = workspace.Name
+	}
+	if autoApply {
+		text += "\nThe workspace applies the runs automatically."
+	}
+
+	a.confirm(fmt.Sprintf("queue %s run", kind), text, expected, func() {
+		a.queueRun(workspace.ID, options)
+	})
+}
+
+func (a *App) queueRun(workspaceID string, options client.RunOptions) {
+	ctx := a.pageCtx
+	if options.Message == "" {
+		options.Message = queuedRunMessage
+	}
+
+	go func() {
+		run, err := a.tfeClient.CreateRun(ctx, workspaceID, options)
+
+		a.QueueUpdateDraw(func() {
+			if err != nil {
+				a.footer.ShowError(fmt.Sprintf("😵 error queuing the run: %s", err))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			a.config.RunID = run.ID
+			a.config.Save()
+			a.activatePage(RunPageName, nil, false)
+			a.footer.Show(fmt.Sprintf("✅ run %s queued", run.ID), tview.Styles.SecondaryTextColor)
+		})
+	}()
+}
+
+// capitalize returns the text with the first letter in upper case.
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func runStatus(c *fake.Client, runID string) tfe.RunStatus {
+	r, _ := c.ReadWorkspaceRun(context.Background(), runID)
+	return r.Status
+}
+
+func TestRunPageApply(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-plan", "add queue", tfe.RunPlanned))
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	showRun(h, "run-plan")
+
+	h.pressRune('a')
+	h.waitFor("Apply the run run-plan of the workspace app-prod?")
+	h.typeText("app-dev")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("ERROR: 😵 type app-prod to confirm")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitForGone("Apply the run run-plan")
+	h.waitFor("run details")
+
+	h.pressRune('a')
+	h.typeText("app-prod")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run-plan: apply confirmed")
+	h.waitUntil(func() bool {
+		return runStatus(c, "run-plan") == tfe.RunConfirmed
+	}, "the run was not applied")
+}
+
+func TestRunPageActionOtherWorkspace(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-prod", fake.NewRun("run-plan", "add queue", tfe.RunPlanned))
+
+	// the selected workspace is not the one of the run
+	cfg := &config.Config{Organization: "acme", Workspace: "app-dev"}
+	h := newHarness(t, cfg, c)
+	showRun(h, "run-plan")
+
+	h.pressRune('d')
+	h.waitFor("Discard the run run-plan of the workspace app-prod?")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("ERROR: 😵 type app-prod to confirm")
+	assert.Equal(t, tfe.RunPlanned, runStatus(c, "run-plan"))
+}
+
+func TestRunPageActionUnknownWorkspace(t *testing.T) {
+	c := newFakeClient()
+	c.AddRun("ws-gone", fake.NewRun("run-plan", "add queue", tfe.RunPlanned))
+
+	cfg := &config.Config{Organization: "acme"}
+	h := newHarness(t, cfg, c)
+	showRun(h, "run-plan")
+
+	h.pressRune('a')
+	h.waitFor("cannot apply the run, its workspace is unknown")
+	assert.Equal(t, tfe.RunPlanned, runStatus(c, "run-plan"))
+}
+
+func TestRunPageActionNotAllowed(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newFakeClient())
+	showRun(h, "run-new")
+
+	h.pressRune('x')
+	h.waitFor("cannot cancel the run while errored")
+	h.pressRune('X')
+	h.waitFor("cannot force cancel the run while errored")
+}
+
+func TestRunPageForceCancel(t *testing.T) {
+	c := newFakeClient()
+	r := c.AddRun("ws-prod", fake.NewRun("run-stuck", "stuck apply", tfe.RunApplying))
+	r.Actions.IsForceCancelable = true
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	showRun(h, "run-stuck")
+
+	h.pressRune('X')
+	h.waitFor("Force cancel the run run-stuck")
+	h.typeText("app-prod")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run-stuck: force canceled")
+	assert.Equal(t, tfe.RunCanceled, runStatus(c, "run-stuck"))
+}
+
+func TestWorkspacePageQueueRun(t *testing.T) {
+	c := newFakeClient()
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+
+	// plan and apply runs only need to be confirmed
+	h.pressRune('n')
+	h.waitFor("Queue a plan and apply run in the workspace app-prod?")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run details")
+	h.waitFor("Message: Queued from terrui")
+	assert.Equal(t, "run-queued-1", h.config.RunID)
+	assert.Equal(t, client.RunOptions{Message: queuedRunMessage}, c.RunOptions("run-queued-1"))
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("workspace details")
+
+	h.pressRune('D')
+	h.waitFor("type app-prod to confirm")
+	h.typeText("app-prod")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitUntil(func() bool {
+		runID := ""
+		h.onUI(func() {
+			runID = h.config.RunID
+		})
+		return runID == "run-queued-2"
+	}, "the destroy run was not queued")
+	assert.Equal(t, client.RunOptions{Message: queuedRunMessage, IsDestroy: true}, c.RunOptions("run-queued-2"))
+}
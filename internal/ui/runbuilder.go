@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const RunBuilderPageName string = "runbuilder"
+
+// runTypes are the run types of the run builder, in the order of the drop
+// down.
+var runTypes = []string{"plan and apply", "plan only", "refresh only", "destroy"}
+
+// shellSafeRX matches the words not needing quotes in a shell.
+var shellSafeRX = regexp.MustCompile(`^[\w.\-/:]+$`)
+
+// RunBuilderPage is a form to queue a run of the current workspace with
+// options, like targets and replaces, completed with the addresses of the
+// current state. It previews the equivalent terraform command.
+type RunBuilderPage struct {
+	*tview.Flex
+
+	app       *App
+	tfeClient client.TFEClient
+	workspace *tfe.Workspace
+	addresses []string
+	// stateErr is why the addresses of the current state could not be read.
+	stateErr error
+
+	form    *tview.Form
+	preview *tview.TextView
+	runType int
+}
+
+func NewRunBuilderPage(app *App, tfeClient client.TFEClient) Page {
+	return &RunBuilderPage{
+		Flex:      tview.NewFlex(),
+		app:       app,
+		tfeClient: tfeClient,
+	}
+}
+
+func (b *RunBuilderPage) Load(ctx context.Context) error {
+	workspace, err := b.tfeClient.ReadWorkspace(ctx, b.app.config.Organization, b.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+	b.workspace = workspace
+
+	// the addresses only complete the fields, so the form works without them,
+	// e.g. before the first apply
+	b.addresses = []string{}
+	b.stateErr = nil
+	state, err := b.currentState(ctx, workspace.ID)
+	switch {
+	case err == nil:
+		b.addresses = stateAddresses(state)
+	case !errors.Is(err, tfe.ErrResourceNotFound):
+		b.stateErr = err
+	}
+
+	return nil
+}
+
+func (b *RunBuilderPage) currentState(ctx context.Context, workspaceID string) (*client.State, error) {
+	sv, err := b.tfeClient.ReadCurrentStateVersion(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the current state version: %w", err)
+	}
+
+	state, err := b.tfeClient.DownloadStateVersion(ctx, sv.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading the state: %w", err)
+	}
+	return state, nil
+}
+
+// stateAddresses returns the addresses of the resources and of their
+// instances, sorted.
+func stateAddresses(state *client.State) []string {
+	unique := map[string]bool{}
+	for _, r := range state.Resources {
+		unique[r.Address()] = true
+		for _, i := range r.Instances {
+			unique[r.InstanceAddress(i)] = true
+		}
+	}
+
+	addresses := make([]string, 0, len(unique))
+	for a := range unique {
+		addresses = append(addresses, a)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+func (b *RunBuilderPage) View() string {
+	b.form = tview.NewForm()
+	b.preview = tview.NewTextView()
+
+	b.form.AddDropDown("Run type", runTypes, 0, func(option string, index int) {
+		b.runType = index
+		b.updatePreview()
+	})
+	b.form.AddInputField("Message", queuedRunMessage, 0, nil, nil)
+	b.form.AddInputField("Targets", "", 0, nil, func(text string) { b.updatePreview() })
+	b.form.AddInputField("Replaces", "", 0, nil, func(text string) { b.updatePreview() })
+	for _, label := range []string{"Targets", "Replaces"} {
+		input := b.form.GetFormItemByLabel(label).(*tview.InputField)
+		input.SetAutocompleteFunc(b.completeAddresses)
+		input.SetPlaceholder("addresses separated by spaces")
+	}
+	b.form.AddButton("Queue", b.queue)
+	b.form.AddButton("Cancel", func() {
+		b.app.goBack(nil)
+	})
+	b.form.SetCancelFunc(func() {
+		b.app.goBack(nil)
+	})
+	b.form.SetBorder(true)
+	b.form.SetBorderPadding(1, 1, 1, 1)
+	b.form.SetTitle(fmt.Sprintf(" queue run in %s ", b.workspace.Name))
+
+	b.preview.SetBorder(true)
+	b.preview.SetBorderPadding(1, 1, 1, 1)
+	b.preview.SetTitle(" terraform command ")
+	b.preview.SetDynamicColors(true)
+	b.updatePreview()
+
+	b.Flex.Clear().
+		AddItem(b.form, 0, 1, true).
+		AddItem(b.preview, 0, 1, false)
+	b.app.SetFocus(b.form)
+
+	if b.stateErr != nil {
+		return "no addresses to complete, the current state could not be read"
+	}
+	return fmt.Sprintf("%d addresses in the current state", len(b.addresses))
+}
+
+// completeAddresses completes the last address of the field with the state
+// addresses containing it.
+func (b *RunBuilderPage) completeAddresses(text string) []string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || strings.HasSuffix(text, " ") {
+		return nil
+	}
+
+	prefix := strings.Join(fields[:len(fields)-1], " ")
+	if prefix != "" {
+		prefix += " "
+	}
+	last := fields[len(fields)-1]
+
+	entries := []string{}
+	for _, a := range b.addresses {
+		if strings.Contains(a, last) && a != last {
+			entries = append(entries, prefix+a)
+		}
+	}
+	return entries
+}
+
+// options returns the run options of the form.
+func (b *RunBuilderPage) options() client.RunOptions {
+	text := func(label string) string {
+		return b.form.GetFormItemByLabel(label).(*tview.InputField).GetText()
+	}
+
+	return client.RunOptions{
+		Message:     strings.TrimSpace(text("Message")),
+		PlanOnly:    b.runType == 1,
+		RefreshOnly: b.runType == 2,
+		IsDestroy:   b.runType == 3,
+		Targets:     splitAddresses(text("Targets")),
+		Replaces:    splitAddresses(text("Replaces")),
+	}
+}
+
+// splitAddresses returns the addresses separated by spaces or commas, nil if
+// there are none.
+func splitAddresses(text string) []string {
+	addresses := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(addresses) == 0 {
+		return nil
+	}
+	return addresses
+}
+
+// validateRunOptions returns an error if the API does not accept the options.
+func validateRunOptions(options client.RunOptions) error {
+	if len(options.Replaces) > 0 && (options.IsDestroy || options.RefreshOnly) {
+		return errors.New("the replaces are only supported by the plan and apply and plan only runs")
+	}
+	return nil
+}
+
+// terraformCommand returns the terraform command equivalent to the run
+// options, with a flag per line.
+func terraformCommand(options client.RunOptions) string {
+	args := []string{"terraform apply"}
+	switch {
+	case options.IsDestroy:
+		args = append(args, "-destroy")
+	case options.RefreshOnly:
+		args = append(args, "-refresh-only")
+	case options.PlanOnly:
+		args = []string{"terraform plan"}
+	}
+
+	for _, t := range options.Targets {
+		args = append(args, fmt.Sprintf("-target=%s", shellQuote(t)))
+	}
+	for _, r := range options.Replaces {
+		args = append(args, fmt.Sprintf("-replace=%s", shellQuote(r)))
+	}
+	return strings.Join(args, " \\\n    ")
+}
+
+// shellQuote returns the word quoted for a shell, if needed.
+func shellQuote(word string) string {
+	if shellSafeRX.MatchString(word) {
+		return word
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(word, "'", `'\''`))
+}
+
+func (b *RunBuilderPage) updatePreview() {
+	if b.preview == nil || b.form.GetFormItemCount() < 4 {
+		return
+	}
+
+	options := b.options()
+	text := fmt.Sprintf("[green]%s[-]", tview.Escape(terraformCommand(options)))
+	if err := validateRunOptions(options); err != nil {
+		text += fmt.Sprintf("\n\n[orangered]%s[-]", err)
+	}
+	b.preview.SetText(text)
+}
+
+func (b *RunBuilderPage) queue() {
+	options := b.options()
+	if err := validateRunOptions(options); err != nil {
+		b.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+		return
+	}
+
+	b.app.confirmQueueRun(b.workspace, options)
+}
+
+func (b *RunBuilderPage) BindKeys() KeyActions {
+	return KeyActions{}
+}
+
+func (b *RunBuilderPage) Crumb() []string {
+	return []string{
+		b.app.config.Organization,
+		b.app.config.Workspace,
+		"runs",
+		"new",
+	}
+}
+
+func (b *RunBuilderPage) Name() string {
+	return RunBuilderPageName
+}
+
+func (b *RunBuilderPage) Footer() string {
+	return ""
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestTerraformCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  client.RunOptions
+		expected string
+	}{
+		{
+			name:     "plan and apply",
+			expected: "terraform apply",
+		},
+		{
+			name:     "plan only with targets",
+			options:  client.RunOptions{PlanOnly: true, Targets: []string{"aws_s3_bucket.logs", `aws_instance.web["a"]`}},
+			expected: "terraform plan \\\n    -target=aws_s3_bucket.logs \\\n    -target='aws_instance.web[\"a\"]'",
+		},
+		{
+			name:     "refresh only",
+			options:  client.RunOptions{RefreshOnly: true},
+			expected: "terraform apply \\\n    -refresh-only",
+		},
+		{
+			name:     "destroy",
+			options:  client.RunOptions{IsDestroy: true, Targets: []string{"module.app"}},
+			expected: "terraform apply \\\n    -destroy \\\n    -target=module.app",
+		},
+		{
+			name:     "replace",
+			options:  client.RunOptions{Replaces: []string{"module.app.aws_instance.web[0]"}},
+			expected: "terraform apply \\\n    -replace='module.app.aws_instance.web[0]'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, terraformCommand(tc.options))
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "aws_s3_bucket.logs", shellQuote("aws_s3_bucket.logs"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
+
+func TestValidateRunOptions(t *testing.T) {
+	assert.NoError(t, validateRunOptions(client.RunOptions{PlanOnly: true, Replaces: []string{"aws_s3_bucket.logs"}}))
+	assert.Error(t, validateRunOptions(client.RunOptions{IsDestroy: true, Replaces: []string{"aws_s3_bucket.logs"}}))
+	assert.Error(t, validateRunOptions(client.RunOptions{RefreshOnly: true, Replaces: []string{"aws_s3_bucket.logs"}}))
+}
+
+func TestRunBuilderPage(t *testing.T) {
+	c := newStateClient()
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+
+	h.pressRune('N')
+	h.waitFor("queue run in app-prod")
+	h.waitFor("addresses in the current state")
+	h.waitFor("terraform apply")
+
+	// run type, plan only
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("terraform plan")
+
+	// message and then targets
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.typeText("web[1")
+	h.waitFor("module.app.aws_instance.web[1]")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("-target='module.app.aws_instance.web[1]'")
+
+	h.pressKey(tcell.KeyTab)
+	h.typeText("aws_s3_bucket.logs")
+	h.waitFor("-replace=aws_s3_bucket.logs")
+
+	// queue button
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("Queue a plan only run in the workspace app-prod?")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("run details")
+
+	assert.Equal(t, client.RunOptions{
+		Message:  queuedRunMessage,
+		PlanOnly: true,
+		Targets:  []string{"module.app.aws_instance.web[1]"},
+		Replaces: []string{"aws_s3_bucket.logs"},
+	}, c.RunOptions(h.config.RunID))
+}
+
+func TestRunBuilderPageWithoutState(t *testing.T) {
+	c := newStateClient()
+	c.FailWith("DownloadStateVersion", errors.New("boom"))
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+
+	h.pressRune('N')
+	h.waitFor("queue run in app-prod")
+	h.waitFor("no addresses to complete, the current state could not be read")
+	h.waitFor("terraform apply")
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const StatePageName string = "state"
+
+// sensitiveNameRX matches the attribute names masked even if the state does
+// not mark them as sensitive, as only the provider schemas know about them.
+var sensitiveNameRX = regexp.MustCompile(`(?i)(password|secret|token|private_key)`)
+
+// StatePage shows a tree of the modules, resources and instances of a state
+// version, with the instance attributes. The sensitive values are masked
+// until revealed.
+type StatePage struct {
+	*tview.Flex
+
+	app       *App
+	tfeClient client.TFEClient
+	version   *client.StateVersion
+	state     *client.State
+
+	tree        *tview.TreeView
+	searchInput *tview.InputField
+	searching   bool
+	reveal      bool
+}
+
+func NewStatePage(app *App, tfeClient client.TFEClient, version *client.StateVersion) Page {
+	s := &StatePage{
+		Flex:        tview.NewFlex(),
+		app:         app,
+		tfeClient:   tfeClient,
+		version:     version,
+		tree:        tview.NewTreeView(),
+		searchInput: tview.NewInputField(),
+	}
+
+	s.searchInput.SetFieldBackgroundColor(s.GetBackgroundColor())
+	s.tree.SetBorder(true)
+	s.tree.SetBorderPadding(0, 1, 1, 1)
+	s.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	s.SetDirection(tview.FlexRow).
+		AddItem(s.searchInput, 2, 0, false).
+		AddItem(s.tree, 0, 1, true)
+
+	return s
+}
+
+func (s *StatePage) Load(ctx context.Context) error {
+	state, err := s.tfeClient.DownloadStateVersion(ctx, s.version.DownloadURL)
+	if err != nil {
+		return fmt.Errorf("error downloading the state: %w", err)
+	}
+	s.state = state
+
+	return nil
+}
+
+func (s *StatePage) View() string {
+	s.renderTree()
+	return "state loaded"
+}
+
+// renderTree builds the tree with the instances whose address contains the
+// search text, keeping the expanded instances and the selected one.
+func (s *StatePage) renderTree() {
+	searchText := s.searchInput.GetText()
+
+	expanded := map[interface{}]bool{}
+	if old := s.tree.GetRoot(); old != nil {
+		old.Walk(func(node, parent *tview.TreeNode) bool {
+			if node.GetReference() != nil && node.IsExpanded() {
+				expanded[node.GetReference()] = true
+			}
+			return true
+		})
+	}
+	var current interface{}
+	if node := s.tree.GetCurrentNode(); node != nil {
+		current = node.GetReference()
+	}
+
+	root := tview.NewTreeNode(fmt.Sprintf("serial %d, terraform %s", s.state.Serial, tview.Escape(s.state.TerraformVersion)))
+	selected := root
+
+	modules := map[string]*tview.TreeNode{}
+	moduleNames := []string{}
+	instances := 0
+	for _, r := range s.state.Resources {
+		resource := tview.NewTreeNode(tview.Escape(r.Address())).SetColor(tcell.ColorYellow)
+		for _, i := range r.Instances {
+			address := r.InstanceAddress(i)
+			if !strings.Contains(address, searchText) {
+				continue
+			}
+
+			instance := tview.NewTreeNode(tview.Escape(address)).SetColor(tcell.ColorGreen)
+			instance.SetReference(address)
+			instance.SetExpanded(expanded[address])
+			if address == current {
+				selected = instance
+			}
+			addStateAttributes(instance, s.maskInstance(i))
+			resource.AddChild(instance)
+			instances++
+		}
+		if len(resource.GetChildren()) == 0 {
+			continue
+		}
+
+		module, ok := modules[r.Module]
+		if !ok {
+			name := r.Module
+			if name == "" {
+				name = "root"
+			}
+			module = tview.NewTreeNode(tview.Escape(name)).SetColor(tcell.ColorAqua)
+			modules[r.Module] = module
+			moduleNames = append(moduleNames, r.Module)
+		}
+		module.AddChild(resource)
+	}
+
+	sort.Strings(moduleNames)
+	for _, m := range moduleNames {
+		root.AddChild(modules[m])
+	}
+
+	s.tree.SetRoot(root)
+	s.tree.SetCurrentNode(selected)
+	s.updateTitle(instances)
+}
+
+func (s *StatePage) updateTitle(instances int) {
+	sensitive := "🔒 sensitive masked"
+	if s.reveal {
+		sensitive = "🔓 sensitive revealed"
+	}
+	s.tree.SetTitle(fmt.Sprintf(" state serial %d | %d instances | %s ", s.state.Serial, instances, sensitive))
+}
+
+// maskInstance returns the instance attributes, with the sensitive values
+// masked unless revealed.
+func (s *StatePage) maskInstance(i client.StateInstance) map[string]interface{} {
+	if s.reveal {
+		return i.Attributes
+	}
+	return maskStateInstance(i)
+}
+
+// maskStateInstance returns the instance attributes with the sensitive values
+// masked, the ones marked as sensitive in the state and the ones named like
+// secrets.
+func maskStateInstance(i client.StateInstance) map[string]interface{} {
+	var value interface{} = i.Attributes
+	for _, p := range i.SensitiveAttributes {
+		value = maskStatePath(value, p)
+	}
+	value = maskSensitiveNames(value)
+
+	attributes, _ := value.(map[string]interface{})
+	return attributes
+}
+
+// maskStatePath returns the value with the attribute at the path masked,
+// copying the maps and lists on the path.
+func maskStatePath(value interface{}, path client.StatePath) interface{} {
+	if len(path) == 0 {
+		return sensitiveValue
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		key, ok := path[0].Key()
+		if _, found := v[key]; !ok || !found {
+			return value
+		}
+		masked := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			masked[k] = child
+		}
+		masked[key] = maskStatePath(v[key], path[1:])
+		return masked
+	case []interface{}:
+		index, ok := path[0].Index()
+		if !ok || index < 0 || index >= len(v) {
+			return value
+		}
+		masked := append([]interface{}{}, v...)
+		masked[index] = maskStatePath(v[index], path[1:])
+		return masked
+	}
+	return value
+}
+
+// maskSensitiveNames returns the value with the strings of the attributes
+// named like secrets masked.
+func maskSensitiveNames(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if s, ok := child.(string); ok && s != "" && sensitiveNameRX.MatchString(k) {
+				masked[k] = sensitiveValue
+				continue
+			}
+			masked[k] = maskSensitiveNames(child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, child := range v {
+			masked[i] = maskSensitiveNames(child)
+		}
+		return masked
+	}
+	return value
+}
+
+// addStateAttributes adds the attributes to the node, sorted by name, with
+// the nested values as child nodes.
+func addStateAttributes(node *tview.TreeNode, value interface{}) {
+	add := func(key string, child interface{}) {
+		switch child.(type) {
+		case map[string]interface{}, []interface{}:
+			n := tview.NewTreeNode(fmt.Sprintf("[magenta::b]%s[-::-]", tview.Escape(key)))
+			addStateAttributes(n, child)
+			node.AddChild(n)
+		default:
+			node.AddChild(tview.NewTreeNode(fmt.Sprintf("[magenta::b]%s[blue::-]: [-]%s", tview.Escape(key), fmtStateValue(child))))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			add(k, v[k])
+		}
+	case []interface{}:
+		for i, child := range v {
+			add(fmt.Sprintf("[%d]", i), child)
+		}
+	}
+}
+
+func fmtStateValue(value interface{}) string {
+	if value == sensitiveValue {
+		return fmt.Sprintf("[gray::i]%s[-::-]", sensitiveValue)
+	}
+
+	text, _ := fmtDiffScalar(diffSide{value: value})
+	return tview.Escape(text)
+}
+
+func (s *StatePage) BindKeys() KeyActions {
+	return KeyActions{
+		KeySlash:     NewKeyAction("search addresses", s.actionSearch, true),
+		KeyR:         NewKeyAction("reveal or mask sensitive values", s.actionToggleReveal, true),
+		tcell.KeyEsc: NewKeyAction("cancel search or go back", s.actionCancelSearch, true),
+	}
+}
+
+func (s *StatePage) actionSearch(ek *tcell.EventKey) *tcell.EventKey {
+	s.searching = true
+
+	s.searchInput.SetFieldBackgroundColor(tview.Styles.ContrastBackgroundColor)
+	s.searchInput.SetLabel("🔎 ")
+	s.searchInput.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			s.clearSearch()
+		case tcell.KeyEnter:
+			s.renderTree()
+		}
+		s.searching = false
+		s.app.SetFocus(s.tree)
+	})
+	s.app.SetFocus(s.searchInput)
+	return nil
+}
+
+func (s *StatePage) actionCancelSearch(ek *tcell.EventKey) *tcell.EventKey {
+	if s.searchInput.GetText() == "" {
+		return s.app.goBack(ek)
+	}
+
+	s.clearSearch()
+	s.renderTree()
+	return nil
+}
+
+func (s *StatePage) clearSearch() {
+	s.searchInput.SetFieldBackgroundColor(s.GetBackgroundColor())
+	s.searchInput.SetLabel("")
+	s.searchInput.SetText("")
+}
+
+func (s *StatePage) actionToggleReveal(ek *tcell.EventKey) *tcell.EventKey {
+	if s.state == nil {
+		return nil
+	}
+
+	s.reveal = !s.reveal
+	s.renderTree()
+	return nil
+}
+
+func (s *StatePage) Crumb() []string {
+	return []string{
+		s.app.config.Organization,
+		s.app.config.Workspace,
+		StateVersionsPageName,
+		fmt.Sprint(s.version.Serial),
+	}
+}
+
+func (s *StatePage) Name() string {
+	return StatePageName
+}
+
+func (s *StatePage) Footer() string {
+	return ""
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func newState(serial int64) *client.State {
+	return &client.State{
+		Version:          4,
+		TerraformVersion: "1.1.9",
+		Serial:           serial,
+		Resources: []client.StateResource{
+			{
+				Mode: "managed",
+				Type: "aws_s3_bucket",
+				Name: "logs",
+				Instances: []client.StateInstance{
+					{Attributes: map[string]interface{}{"bucket": "acme-logs", "tags": map[string]interface{}{"team": "platform"}}},
+				},
+			},
+			{
+				Module: "module.app",
+				Mode:   "managed",
+				Type:   "aws_instance",
+				Name:   "web",
+				Instances: []client.StateInstance{
+					{
+						IndexKey:   float64(0),
+						Attributes: map[string]interface{}{"id": "i-0a1", "user_data": "echo s3cr3t"},
+						SensitiveAttributes: []client.StatePath{
+							{{Type: "get_attr", Value: "user_data"}},
+						},
+					},
+					{
+						IndexKey:   float64(1),
+						Attributes: map[string]interface{}{"id": "i-0a2", "db_password": "hunter2"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newStateClient() *fake.Client {
+	c := newFakeClient()
+	for serial := int64(1); serial <= 2; serial++ {
+		state := newState(serial)
+		c.AddStateVersion("ws-prod", fake.NewStateVersion(fmt.Sprintf("sv-%d", serial), state), state)
+	}
+	return c
+}
+
+func TestMaskStatePath(t *testing.T) {
+	value := map[string]interface{}{
+		"name": "db",
+		"creds": []interface{}{
+			map[string]interface{}{"user": "admin", "password": "hunter2"},
+		},
+		"headers": map[string]interface{}{"token": "abc"},
+	}
+
+	masked := maskStatePath(value, client.StatePath{
+		{Type: "get_attr", Value: "creds"},
+		{Type: "index", Value: map[string]interface{}{"value": float64(0), "type": "number"}},
+		{Type: "get_attr", Value: "password"},
+	})
+	masked = maskStatePath(masked, client.StatePath{
+		{Type: "get_attr", Value: "headers"},
+		{Type: "index", Value: map[string]interface{}{"value": "token", "type": "string"}},
+	})
+	masked = maskStatePath(masked, client.StatePath{{Type: "get_attr", Value: "missing"}})
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "db",
+		"creds": []interface{}{
+			map[string]interface{}{"user": "admin", "password": sensitiveValue},
+		},
+		"headers": map[string]interface{}{"token": sensitiveValue},
+	}, masked)
+	assert.Equal(t, "hunter2", value["creds"].([]interface{})[0].(map[string]interface{})["password"], "the value is not changed")
+}
+
+func TestMaskSensitiveNames(t *testing.T) {
+	value := map[string]interface{}{
+		"name":          "db",
+		"password":      "hunter2",
+		"client_secret": "",
+		"nested":        []interface{}{map[string]interface{}{"private_key": "-----BEGIN", "public_key": "ssh-rsa"}},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":          "db",
+		"password":      sensitiveValue,
+		"client_secret": "",
+		"nested":        []interface{}{map[string]interface{}{"private_key": sensitiveValue, "public_key": "ssh-rsa"}},
+	}, maskSensitiveNames(value))
+}
+
+func TestStateVersionsPage(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, newStateClient())
+	h.waitFor("workspace details")
+
+	h.pressRune('S')
+	h.waitFor("<states>")
+	h.waitFor("sv-2")
+	h.waitFor("total states: 2")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<2>")
+	h.waitFor("state serial 2 | 3 instances | 🔒 sensitive masked")
+	h.waitFor("module.app")
+	h.waitFor("aws_s3_bucket.logs")
+	h.waitFor("module.app.aws_instance.web[1]")
+
+	h.pressRune('/')
+	h.typeText("web[1]")
+	h.pressKey(tcell.KeyEnter)
+	h.waitForGone("aws_s3_bucket.logs")
+	h.waitFor("state serial 2 | 1 instances")
+
+	// root, module, resource and the instance
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor(`id: "i-0a2"`)
+	h.waitFor("db_password: (sensitive value)")
+
+	h.pressRune('r')
+	h.waitFor("🔓 sensitive revealed")
+	h.waitFor(`db_password: "hunter2"`)
+
+	h.pressRune('r')
+	h.waitFor("db_password: (sensitive value)")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("aws_s3_bucket.logs")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("<states>")
+}
+
+func TestStateVersionsPageOpensRun(t *testing.T) {
+	c := newFakeClient()
+	state := newState(1)
+	sv := fake.NewStateVersion("sv-1", state)
+	sv.RunID = "run-old"
+	c.AddStateVersion("ws-prod", sv, state)
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+	h.typeText(":states")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("sv-1")
+
+	h.pressRune('R')
+	h.waitFor("run details")
+	assert.Equal(t, "run-old", h.config.RunID)
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v2"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const StateDiffPageName string = "statediff"
+
+// StateDiffPage shows the resource instances added, removed and changed
+// between two state versions, with the attribute diff of the changed ones.
+type StateDiffPage struct {
+	*tview.TextView
+
+	app       *App
+	tfeClient client.TFEClient
+	from      *client.StateVersion
+	to        *client.StateVersion
+	fromState *client.State
+	toState   *client.State
+	reveal    bool
+}
+
+type stateDiffInfo struct {
+	From string `yaml:"From"`
+	To   string `yaml:"To"`
+}
+
+// stateDiffSummary is the number of instances added, changed and removed.
+type stateDiffSummary struct {
+	added   int
+	changed int
+	removed int
+}
+
+// NewStateDiffPage creates the page showing the diff from the older to the
+// newer of the two state versions.
+func NewStateDiffPage(app *App, tfeClient client.TFEClient, a, b *client.StateVersion) Page {
+	from, to := a, b
+	if from.Serial > to.Serial {
+		from, to = to, from
+	}
+
+	return &StateDiffPage{
+		TextView:  tview.NewTextView(),
+		app:       app,
+		tfeClient: tfeClient,
+		from:      from,
+		to:        to,
+	}
+}
+
+func (d *StateDiffPage) Load(ctx context.Context) error {
+	fromState, err := d.tfeClient.DownloadStateVersion(ctx, d.from.DownloadURL)
+	if err != nil {
+		return fmt.Errorf("error downloading the state serial %d: %w", d.from.Serial, err)
+	}
+	d.fromState = fromState
+
+	toState, err := d.tfeClient.DownloadStateVersion(ctx, d.to.DownloadURL)
+	if err != nil {
+		return fmt.Errorf("error downloading the state serial %d: %w", d.to.Serial, err)
+	}
+	d.toState = toState
+
+	return nil
+}
+
+func (d *StateDiffPage) View() string {
+	d.SetBorder(true)
+	d.SetBorderPadding(0, 1, 1, 1)
+	d.SetDynamicColors(true)
+	d.render()
+
+	return "state versions compared"
+}
+
+func (d *StateDiffPage) render() {
+	info := stateDiffInfo{
+		From: fmt.Sprintf("serial %d, %s, %s", d.from.Serial, d.from.ID, fmtTime(d.from.CreatedAt)),
+		To:   fmt.Sprintf("serial %d, %s, %s", d.to.Serial, d.to.ID, fmtTime(d.to.CreatedAt)),
+	}
+	yamlData, _ := yaml.Marshal(info)
+
+	lines, summary := diffStates(d.fromState, d.toState, d.reveal)
+
+	text := []string{
+		colorizeYAML(string(yamlData)),
+		fmt.Sprintf("[green]%d added[-], [yellow]%d changed[-], [red]%d removed[-]", summary.added, summary.changed, summary.removed),
+		"",
+	}
+	for _, l := range lines {
+		text = append(text, l.colorize())
+	}
+	d.SetText(strings.Join(text, "\n"))
+
+	sensitive := "🔒 sensitive masked"
+	if d.reveal {
+		sensitive = "🔓 sensitive revealed"
+	}
+	d.SetTitle(fmt.Sprintf(" state serial %d → %d | %s ", d.from.Serial, d.to.Serial, sensitive))
+}
+
+// diffStates returns the instances added, removed and changed, sorted by
+// address, with the changed attributes of the changed instances.
+func diffStates(from, to *client.State, reveal bool) ([]diffLine, stateDiffSummary) {
+	fromInstances := stateInstances(from)
+	toInstances := stateInstances(to)
+
+	addresses := []string{}
+	for address := range fromInstances {
+		addresses = append(addresses, address)
+	}
+	for address := range toInstances {
+		if _, ok := fromInstances[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	lines := []diffLine{}
+	summary := stateDiffSummary{}
+	for _, address := range addresses {
+		before, inFrom := fromInstances[address]
+		after, inTo := toInstances[address]
+
+		switch {
+		case !inFrom:
+			lines = append(lines, diffLine{symbol: "+", value: address})
+			summary.added++
+		case !inTo:
+			lines = append(lines, diffLine{symbol: "-", value: address})
+			summary.removed++
+		default:
+			changed := changedLines(diffChange(stateDiffSide(before, reveal), stateDiffSide(after, reveal)))
+			if len(changed) == 0 {
+				continue
+			}
+
+			lines = append(lines, diffLine{symbol: "~", value: address})
+			for _, l := range changed {
+				l.depth++
+				lines = append(lines, l)
+			}
+			summary.changed++
+		}
+	}
+
+	return lines, summary
+}
+
+// stateInstances returns the resource instances of the state by address.
+func stateInstances(state *client.State) map[string]client.StateInstance {
+	instances := map[string]client.StateInstance{}
+	for _, r := range state.Resources {
+		for _, i := range r.Instances {
+			instances[r.InstanceAddress(i)] = i
+		}
+	}
+	return instances
+}
+
+// stateDiffSide returns the instance attributes with the sensitive ones
+// marked, unless revealed.
+func stateDiffSide(i client.StateInstance, reveal bool) diffSide {
+	side := diffSide{value: i.Attributes}
+	if !reveal {
+		side.sensitive = sensitiveShape(maskStateInstance(i))
+	}
+	return side
+}
+
+// sensitiveShape returns the shape of the masked value, with the masked
+// values true.
+func sensitiveShape(masked interface{}) interface{} {
+	switch v := masked.(type) {
+	case map[string]interface{}:
+		shape := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			shape[k] = sensitiveShape(child)
+		}
+		return shape
+	case []interface{}:
+		shape := make([]interface{}, len(v))
+		for i, child := range v {
+			shape[i] = sensitiveShape(child)
+		}
+		return shape
+	}
+	return masked == sensitiveValue
+}
+
+func (d *StateDiffPage) BindKeys() KeyActions {
+	return KeyActions{
+		KeyR: NewKeyAction("reveal or mask sensitive values", d.actionToggleReveal, true),
+	}
+}
+
+func (d *StateDiffPage) actionToggleReveal(ek *tcell.EventKey) *tcell.EventKey {
+	if d.fromState == nil || d.toState == nil {
+		return nil
+	}
+
+	d.reveal = !d.reveal
+	d.render()
+	return nil
+}
+
+func (d *StateDiffPage) Crumb() []string {
+	return []string{
+		d.app.config.Organization,
+		d.app.config.Workspace,
+		StateVersionsPageName,
+		fmt.Sprintf("%d..%d", d.from.Serial, d.to.Serial),
+	}
+}
+
+func (d *StateDiffPage) Name() string {
+	return StateDiffPageName
+}
+
+func (d *StateDiffPage) Footer() string {
+	return ""
+}
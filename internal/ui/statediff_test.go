@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+// newChangedState returns the state with the logs bucket tagged for another
+// team, the second web instance removed and a queue added.
+func newChangedState(serial int64) *client.State {
+	state := newState(serial)
+	state.Resources[0].Instances[0].Attributes = map[string]interface{}{
+		"bucket": "acme-logs",
+		"tags":   map[string]interface{}{"team": "security"},
+	}
+	state.Resources[1].Instances[0].Attributes = map[string]interface{}{"id": "i-0a1", "user_data": "echo n3w"}
+	state.Resources[1].Instances = state.Resources[1].Instances[:1]
+	state.Resources = append(state.Resources, client.StateResource{
+		Mode:      "managed",
+		Type:      "aws_sqs_queue",
+		Name:      "jobs",
+		Instances: []client.StateInstance{{Attributes: map[string]interface{}{"name": "jobs"}}},
+	})
+	return state
+}
+
+func TestDiffStates(t *testing.T) {
+	lines, summary := diffStates(newState(1), newChangedState(2), false)
+
+	text := []string{}
+	for _, l := range lines {
+		text = append(text, l.String())
+	}
+	assert.Equal(t, []string{
+		`~ aws_s3_bucket.logs`,
+		`    ~ tags: {`,
+		`        ~ team: "platform" -> "security"`,
+		`      }`,
+		`+ aws_sqs_queue.jobs`,
+		`~ module.app.aws_instance.web[0]`,
+		`    ~ user_data: (sensitive value)`,
+		`- module.app.aws_instance.web[1]`,
+	}, text)
+	assert.Equal(t, stateDiffSummary{added: 1, changed: 2, removed: 1}, summary)
+
+	lines, _ = diffStates(newState(1), newChangedState(2), true)
+	assert.Contains(t, lines, diffLine{depth: 1, symbol: "~", key: "user_data", value: `"echo s3cr3t" -> "echo n3w"`})
+
+	lines, summary = diffStates(newState(1), newState(2), false)
+	assert.Empty(t, lines)
+	assert.Equal(t, stateDiffSummary{}, summary)
+}
+
+func TestStateDiffPage(t *testing.T) {
+	c := newFakeClient()
+	for _, state := range []*client.State{newState(1), newChangedState(2), newState(3)} {
+		c.AddStateVersion("ws-prod", fake.NewStateVersion(fmt.Sprintf("sv-%d", state.Serial), state), state)
+	}
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+	h.pressRune('S')
+	h.waitFor("sv-3")
+
+	h.pressRune('d')
+	h.waitFor("mark two state versions with space to diff them")
+
+	// the newest first, a third mark unmarks the oldest one
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyDown)
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyDown)
+	h.pressRune(' ')
+	h.waitFor("● 1")
+	h.waitForGone("● 3")
+
+	h.pressRune('d')
+	h.waitFor("<1..2>")
+	h.waitFor("state serial 1 → 2 | 🔒 sensitive masked")
+	h.waitFor("1 added, 2 changed, 1 removed")
+	h.waitFor("+ aws_sqs_queue.jobs")
+	h.waitFor("user_data: (sensitive value)")
+
+	h.pressRune('r')
+	h.waitFor(`user_data: "echo s3cr3t" -> "echo n3w"`)
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("<states>")
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const StateVersionsPageName string = "states"
+
+type StateVersionsPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	versions  *client.StateVersionList
+
+	// marked are the state versions to diff, at most two.
+	marked []*client.StateVersion
+
+	table *tview.Table
+}
+
+func NewStateVersionsPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &StateVersionsPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (s *StateVersionsPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (s *StateVersionsPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	versions, err := s.tfeClient.ListWorkspaceStateVersions(ctx, s.app.config.Organization, s.app.config.Workspace, pageNumber)
+	if err != nil {
+		return fmt.Errorf("error reading the state versions: %w", err)
+	}
+	s.versions = versions
+
+	return nil
+}
+
+func (s *StateVersionsPageSource) Columns() []Column {
+	return []Column{
+		{Name: "SERIAL"},
+		{Name: "CREATED AT"},
+		{Name: "RUN"},
+		{Name: "RESOURCES"},
+		{Name: "ID"},
+	}
+}
+
+func (s *StateVersionsPageSource) RenderRows(table *tview.Table) {
+	s.table = table
+
+	for i, sv := range s.versions.Items {
+		r := i + 1
+
+		resources := "processing"
+		if sv.ResourcesProcessed {
+			resources = fmt.Sprint(sv.ResourceCount())
+		}
+
+		serial := fmt.Sprint(sv.Serial)
+		if s.isMarked(sv) {
+			serial = "● " + serial
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(serial).SetExpansion(1).SetReference(sv))
+		table.SetCell(r, 1, tview.NewTableCell(fmtTime(sv.CreatedAt)).SetExpansion(2).SetReference(sv.CreatedAt))
+		table.SetCell(r, 2, tview.NewTableCell(sv.RunID).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(resources).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(sv.ID).SetExpansion(1))
+	}
+}
+
+// selected returns the state version in the selected row.
+func (s *StateVersionsPageSource) selected() *client.StateVersion {
+	if s.table == nil {
+		return nil
+	}
+
+	row, _ := s.table.GetSelection()
+	sv, _ := s.table.GetCell(row, 0).GetReference().(*client.StateVersion)
+	return sv
+}
+
+func (s *StateVersionsPageSource) BindKeys(l *ListPage) KeyActions {
+	return KeyActions{
+		KeyShiftR: NewKeyAction("open the state version run", s.actionShowRun, true),
+		KeySpace:  NewKeyAction("mark state version", s.actionMark(l), true),
+		KeyD:      NewKeyAction("diff marked state versions", s.actionDiff, true),
+	}
+}
+
+// isMarked returns true if the state version is marked to diff, the same
+// state version is read again when the list is refreshed.
+func (s *StateVersionsPageSource) isMarked(sv *client.StateVersion) bool {
+	for _, m := range s.marked {
+		if m.ID == sv.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// actionMark marks or unmarks the selected state version, marking a third one
+// unmarks the oldest mark.
+func (s *StateVersionsPageSource) actionMark(l *ListPage) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		sv := s.selected()
+		if sv == nil {
+			return nil
+		}
+
+		marked := []*client.StateVersion{}
+		for _, m := range s.marked {
+			if m.ID != sv.ID {
+				marked = append(marked, m)
+			}
+		}
+		if len(marked) == len(s.marked) {
+			marked = append(marked, sv)
+		}
+		if len(marked) > 2 {
+			marked = marked[1:]
+		}
+		s.marked = marked
+
+		l.Repaint()
+		return nil
+	}
+}
+
+func (s *StateVersionsPageSource) actionDiff(ek *tcell.EventKey) *tcell.EventKey {
+	if len(s.marked) != 2 {
+		s.app.footer.ShowError("😵 mark two state versions with space to diff them")
+		return nil
+	}
+
+	s.app.activatePage(StateDiffPageName, NewStateDiffPage(s.app, s.tfeClient, s.marked[0], s.marked[1]), false)
+	return nil
+}
+
+func (s *StateVersionsPageSource) actionShowRun(ek *tcell.EventKey) *tcell.EventKey {
+	sv := s.selected()
+	if sv == nil {
+		return nil
+	}
+	if sv.RunID == "" {
+		s.app.footer.ShowError("😵 the state version was not created by a run")
+		return nil
+	}
+
+	s.app.config.RunID = sv.RunID
+	s.app.config.Save()
+	s.app.activatePage(RunPageName, nil, false)
+	return nil
+}
+
+func (s *StateVersionsPageSource) Crumb() []string {
+	return []string{
+		s.app.config.Organization,
+		s.app.config.Workspace,
+		StateVersionsPageName,
+	}
+}
+
+func (s *StateVersionsPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		sv := s.selected()
+		if sv == nil {
+			return nil
+		}
+
+		s.app.activatePage(StatePageName, NewStatePage(s.app, s.tfeClient, sv), false)
+		return nil
+	}
+}
+
+func (s *StateVersionsPageSource) Name() string {
+	return "state version"
+}
+
+func (s *StateVersionsPageSource) NameList() string {
+	return StateVersionsPageName
+}
+
+func (s *StateVersionsPageSource) Empty() bool {
+	return s.versions == nil || len(s.versions.Items) == 0
+}
+
+func (s *StateVersionsPageSource) CurrentPage() int {
+	return s.versions.CurrentPage
+}
+
+func (s *StateVersionsPageSource) TotalCount() int {
+	return s.versions.TotalCount
+}
+
+func (s *StateVersionsPageSource) TotalPages() int {
+	return s.versions.TotalPages
+}
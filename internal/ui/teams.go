@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const TeamsPageName string = "teams"
+
+type TeamsPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	accesses  *tfe.TeamAccessList
+}
+
+func NewTeamsPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &TeamsPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (t *TeamsPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (t *TeamsPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	workspace, err := t.tfeClient.ReadWorkspace(ctx, t.app.config.Organization, t.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+
+	accesses, err := t.tfeClient.ListWorkspaceTeamAccesses(ctx, workspace.ID)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace accesses: %w", err)
+	}
+	t.accesses = accesses
+
+	return nil
+}
+
+func (t *TeamsPageSource) Columns() []Column {
+	return []Column{
+		{Name: "TEAM"},
+		{Name: "ACCESS"},
+		{Name: "RUNS"},
+		{Name: "VARIABLES"},
+		{Name: "STATE VERSIONS"},
+		{Name: "LOCKING"},
+	}
+}
+
+func (t *TeamsPageSource) RenderRows(table *tview.Table) {
+	for i, a := range t.accesses.Items {
+		r := i + 1
+		table.SetCell(r, 0, tview.NewTableCell(a.Team.Name).SetExpansion(2))
+		table.SetCell(r, 1, tview.NewTableCell(string(a.Access)).SetExpansion(1))
+		table.SetCell(r, 2, tview.NewTableCell(string(a.Runs)).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(string(a.Variables)).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(string(a.StateVersions)).SetExpansion(1))
+		table.SetCell(r, 5, tview.NewTableCell(fmt.Sprint(a.WorkspaceLocking)).SetExpansion(1))
+	}
+}
+
+func (t *TeamsPageSource) Crumb() []string {
+	return []string{
+		t.app.config.Organization,
+		t.app.config.Workspace,
+		TeamsPageName,
+	}
+}
+
+func (t *TeamsPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		return nil
+	}
+}
+
+func (t *TeamsPageSource) Name() string {
+	return "team"
+}
+
+func (t *TeamsPageSource) NameList() string {
+	return TeamsPageName
+}
+
+func (t *TeamsPageSource) Empty() bool {
+	return t.accesses == nil || len(t.accesses.Items) == 0
+}
+
+func (t *TeamsPageSource) CurrentPage() int {
+	return t.accesses.CurrentPage
+}
+
+func (t *TeamsPageSource) TotalCount() int {
+	return t.accesses.TotalCount
+}
+
+func (t *TeamsPageSource) TotalPages() int {
+	return t.accesses.TotalPages
+}
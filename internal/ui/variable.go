@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const VariablePageName string = "variable"
+
+// variableCategories are the categories of the variables, in the order of the
+// drop down.
+var variableCategories = []tfe.CategoryType{tfe.CategoryTerraform, tfe.CategoryEnv}
+
+// VariablePage is a form to create a variable of the current workspace, or to
+// edit one. The values of the sensitive variables are write only, so they are
+// kept unless a new value is typed.
+type VariablePage struct {
+	*tview.Flex
+
+	app       *App
+	tfeClient client.TFEClient
+	workspace *tfe.Workspace
+	variable  *tfe.Variable
+	category  tfe.CategoryType
+
+	form    *tview.Form
+	preview *tview.TextView
+}
+
+// NewVariablePage returns the page to edit the variable, or to create one if
+// the variable is nil.
+func NewVariablePage(app *App, tfeClient client.TFEClient, variable *tfe.Variable) *VariablePage {
+	return &VariablePage{
+		Flex:      tview.NewFlex(),
+		app:       app,
+		tfeClient: tfeClient,
+		variable:  variable,
+		category:  tfe.CategoryTerraform,
+	}
+}
+
+func (v *VariablePage) Load(ctx context.Context) error {
+	workspace, err := v.tfeClient.ReadWorkspace(ctx, v.app.config.Organization, v.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+	v.workspace = workspace
+
+	return nil
+}
+
+func (v *VariablePage) View() string {
+	v.form = tview.NewForm()
+	v.preview = tview.NewTextView()
+
+	variable := v.variable
+	if variable == nil {
+		variable = &tfe.Variable{Category: tfe.CategoryTerraform}
+	}
+
+	value := variable.Value
+	if variable.Sensitive {
+		value = ""
+	}
+
+	v.form.AddInputField("Key", variable.Key, 0, nil, nil)
+	v.form.AddInputField("Value", value, 0, nil, func(text string) { v.updatePreview() })
+	v.form.AddInputField("Description", variable.Description, 0, nil, nil)
+	if v.variable == nil {
+		categories := []string{}
+		for _, c := range variableCategories {
+			categories = append(categories, string(c))
+		}
+		v.form.AddDropDown("Category", categories, 0, func(option string, index int) {
+			v.category = variableCategories[index]
+		})
+	}
+	v.form.AddCheckbox("HCL", variable.HCL, func(checked bool) { v.updatePreview() })
+	v.form.AddCheckbox("Sensitive", variable.Sensitive, func(checked bool) { v.updatePreview() })
+	if variable.Sensitive {
+		v.valueInput().SetPlaceholder("write only, leave empty to keep the value")
+	}
+
+	v.form.AddButton("Save", v.save)
+	v.form.AddButton("Editor", v.edit)
+	v.form.AddButton("Cancel", func() {
+		v.app.goBack(nil)
+	})
+	v.form.SetCancelFunc(func() {
+		v.app.goBack(nil)
+	})
+	v.form.SetBorder(true)
+	v.form.SetBorderPadding(1, 1, 1, 1)
+	if v.variable == nil {
+		v.form.SetTitle(fmt.Sprintf(" new variable in %s ", v.workspace.Name))
+	} else {
+		v.form.SetTitle(fmt.Sprintf(" edit variable %s ", v.variable.Key))
+	}
+
+	v.preview.SetBorder(true)
+	v.preview.SetBorderPadding(1, 1, 1, 1)
+	v.preview.SetTitle(" value ")
+	v.preview.SetDynamicColors(true)
+	v.updatePreview()
+
+	v.Flex.Clear().
+		AddItem(v.form, 0, 1, true).
+		AddItem(v.preview, 0, 1, false)
+	v.app.SetFocus(v.form)
+
+	if v.variable == nil {
+		return "new variable"
+	}
+	return fmt.Sprintf("variable %s loaded", v.variable.Key)
+}
+
+func (v *VariablePage) valueInput() *tview.InputField {
+	return v.form.GetFormItemByLabel("Value").(*tview.InputField)
+}
+
+func (v *VariablePage) checked(label string) bool {
+	return v.form.GetFormItemByLabel(label).(*tview.Checkbox).IsChecked()
+}
+
+// options returns the variable options of the form, the value of a sensitive
+// variable is kept if no new value was typed.
+func (v *VariablePage) options() client.VariableOptions {
+	text := func(label string) string {
+		return v.form.GetFormItemByLabel(label).(*tview.InputField).GetText()
+	}
+
+	options := client.VariableOptions{
+		Key:         strings.TrimSpace(text("Key")),
+		Description: text("Description"),
+		Category:    v.category,
+		HCL:         v.checked("HCL"),
+		Sensitive:   v.checked("Sensitive"),
+	}
+	if v.variable != nil {
+		options.Category = v.variable.Category
+	}
+
+	value := text("Value")
+	if v.variable == nil || !v.variable.Sensitive || value != "" {
+		options.Value = &value
+	}
+	return options
+}
+
+// validateVariable returns an error if the API does not accept the options
+// for the variable, nil when it is created.
+func validateVariable(variable *tfe.Variable, options client.VariableOptions) error {
+	if options.Key == "" {
+		return errors.New("the key is required")
+	}
+	if options.HCL && options.Category == tfe.CategoryEnv {
+		return errors.New("environment variables cannot be HCL")
+	}
+	if variable != nil && variable.Sensitive && !options.Sensitive {
+		return errors.New("sensitive variables cannot be made non sensitive")
+	}
+	return nil
+}
+
+func (v *VariablePage) updatePreview() {
+	if v.preview == nil || v.form.GetFormItemByLabel("Sensitive") == nil {
+		return
+	}
+
+	options := v.options()
+	switch {
+	case options.Value == nil:
+		v.preview.SetText("[::d]the sensitive value is kept[::-]")
+	case options.Sensitive:
+		v.preview.SetText("******")
+	default:
+		v.preview.SetText(tview.Escape(*options.Value))
+	}
+}
+
+// edit opens the value in the editor of the user, for the multi-line values.
+func (v *VariablePage) edit() {
+	extension := ".txt"
+	if v.checked("HCL") {
+		extension = ".hcl"
+	}
+
+	value, err := editText(v.app, v.valueInput().GetText(), extension)
+	if err != nil {
+		v.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+		return
+	}
+	v.valueInput().SetText(value)
+}
+
+// save creates the variable or, after confirming it, updates the variable.
+// Making a variable sensitive can't be undone, so the key must be typed.
+func (v *VariablePage) save() {
+	options := v.options()
+	if err := validateVariable(v.variable, options); err != nil {
+		v.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+		return
+	}
+
+	if v.variable == nil {
+		v.saveVariable(options)
+		return
+	}
+
+	text := fmt.Sprintf("Update the variable [::b]%s[::-] of the workspace [::b]%s[::-]?",
+		tview.Escape(v.variable.Key), tview.Escape(v.workspace.Name))
+	expected := ""
+	if options.Sensitive && !v.variable.Sensitive {
+		text += "\nThe value becomes write only and can't be made non sensitive again."
+		expected = v.variable.Key
+	}
+	v.app.confirm("update variable", text, expected, func() {
+		v.saveVariable(options)
+	})
+}
+
+func (v *VariablePage) saveVariable(options client.VariableOptions) {
+	ctx := v.app.pageCtx
+	workspaceID := v.workspace.ID
+	variable := v.variable
+
+	go func() {
+		var err error
+		if variable == nil {
+			_, err = v.tfeClient.CreateVariable(ctx, workspaceID, options)
+		} else {
+			_, err = v.tfeClient.UpdateVariable(ctx, workspaceID, variable.ID, options)
+		}
+
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.footer.ShowError(fmt.Sprintf("😵 error saving the variable: %s", err))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			v.app.goBack(nil)
+			if page, ok := v.app.currentPage.(RefreshablePage); ok {
+				v.app.reloadPage(page)
+			}
+			v.app.footer.Show(fmt.Sprintf("✅ variable %s saved", options.Key), tview.Styles.SecondaryTextColor)
+		})
+	}()
+}
+
+func (v *VariablePage) BindKeys() KeyActions {
+	return KeyActions{}
+}
+
+func (v *VariablePage) Crumb() []string {
+	crumb := []string{
+		v.app.config.Organization,
+		v.app.config.Workspace,
+		VariablesPageName,
+	}
+	if v.variable == nil {
+		return append(crumb, "new")
+	}
+	return append(crumb, v.variable.Key)
+}
+
+func (v *VariablePage) Name() string {
+	return VariablePageName
+}
+
+func (v *VariablePage) Footer() string {
+	return ""
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func findVariable(c *fake.Client, workspaceID, key string) *tfe.Variable {
+	variables, _ := c.ListWorkspaceVariables(context.Background(), workspaceID, -1)
+	for _, v := range variables.Items {
+		if v.Key == key {
+			return v
+		}
+	}
+	return nil
+}
+
+func showVariables(h *harness) {
+	h.waitFor("workspace details")
+	h.pressRune(':')
+	h.typeText("vars")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("db_password")
+}
+
+func TestValidateVariable(t *testing.T) {
+	sensitive := &tfe.Variable{Key: "db_password", Sensitive: true}
+
+	assert.NoError(t, validateVariable(nil, client.VariableOptions{Key: "tags", Category: tfe.CategoryTerraform, HCL: true}))
+	assert.EqualError(t, validateVariable(nil, client.VariableOptions{}), "the key is required")
+	assert.EqualError(t, validateVariable(nil, client.VariableOptions{Key: "TF_LOG", Category: tfe.CategoryEnv, HCL: true}),
+		"environment variables cannot be HCL")
+	assert.EqualError(t, validateVariable(sensitive, client.VariableOptions{Key: "db_password"}),
+		"sensitive variables cannot be made non sensitive")
+}
+
+func TestVariablesPageCreate(t *testing.T) {
+	edit := editText
+	t.Cleanup(func() { editText = edit })
+	editText = func(a *App, text, extension string) (string, error) {
+		return "{\n  team = \"platform\"\n}", nil
+	}
+
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	h.pressRune('n')
+	h.waitFor("new variable in app-prod")
+	h.typeText("tags")
+	for i := 0; i < 4; i++ {
+		h.pressKey(tcell.KeyTab)
+	}
+	// hcl
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	// editor
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor(`team = "platform"`)
+
+	h.pressKey(tcell.KeyBacktab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("variable tags saved")
+	h.waitFor("<variables>")
+	h.waitUntil(func() bool {
+		return findVariable(c, "ws-prod", "tags") != nil
+	}, "the variable was not created")
+
+	v := findVariable(c, "ws-prod", "tags")
+	assert.Equal(t, "{\n  team = \"platform\"\n}", v.Value)
+	assert.Equal(t, tfe.CategoryTerraform, v.Category)
+	assert.True(t, v.HCL)
+}
+
+func TestVariablesPageEditSensitive(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("edit variable db_password")
+	h.waitFor("write only, leave empty to keep the value")
+	h.waitFor("the sensitive value is kept")
+	assert.NotContains(t, h.text(), "hunter2")
+
+	// key, value, description, hcl and sensitive
+	for i := 0; i < 4; i++ {
+		h.pressKey(tcell.KeyTab)
+	}
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("sensitive variables cannot be made non sensitive")
+
+	h.pressKey(tcell.KeyBacktab)
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyBacktab)
+	h.pressKey(tcell.KeyBacktab)
+	h.typeText("database password")
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("Update the variable db_password of the workspace app-prod?")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("variable db_password saved")
+
+	v := findVariable(c, "ws-prod", "db_password")
+	require.NotNil(t, v)
+	assert.Equal(t, "database password", v.Description)
+	assert.Equal(t, "hunter2", v.Value)
+}
+
+func TestVariablesPageDelete(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	h.pressRune('d')
+	h.waitFor("Delete the variable region of the workspace app-prod?")
+	h.typeText("region")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("variable region deleted")
+	h.waitForGone("eu-west-1")
+	assert.Nil(t, findVariable(c, "ws-prod", "region"))
+}
+
+func TestVariablesPagePagination(t *testing.T) {
+	c := newFakeClient()
+	for i := 0; i < 35; i++ {
+		c.AddVariable("ws-prod", fake.NewVariable(fmt.Sprintf("var-page-%d", i), fmt.Sprintf("key_%02d", i), "value"))
+	}
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+	h.waitFor("page 1 of 2, total variables: 37")
+
+	h.pressKey(tcell.KeyCtrlJ)
+	h.waitFor("page 2 of 2, total variables: 37")
+	h.waitFor("key_34")
+}
@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/varfile"
+)
+
+const VariablesPageName string = "variables"
+
+type VariablesPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	workspace *tfe.Workspace
+	variables *tfe.VariableList
+
+	table *tview.Table
+}
+
+func NewVariablesPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &VariablesPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (v *VariablesPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (v *VariablesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	workspace, err := v.tfeClient.ReadWorkspace(ctx, v.app.config.Organization, v.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+	v.workspace = workspace
+
+	variables, err := v.tfeClient.ListWorkspaceVariables(ctx, workspace.ID, pageNumber)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace variables: %w", err)
+	}
+	v.variables = variables
+
+	return nil
+}
+
+func (v *VariablesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "KEY"},
+		{Name: "VALUE"},
+		{Name: "CATEGORY"},
+		{Name: "HCL"},
+		{Name: "SENSITIVE"},
+		{Name: "DESCRIPTION"},
+	}
+}
+
+func (v *VariablesPageSource) RenderRows(table *tview.Table) {
+	v.table = table
+
+	for i, variable := range v.variables.Items {
+		r := i + 1
+
+		value := variable.Value
+		if variable.Sensitive {
+			value = "******"
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(variable.Key).SetExpansion(1).SetReference(variable))
+		table.SetCell(r, 1, tview.NewTableCell(value).SetExpansion(2))
+		table.SetCell(r, 2, tview.NewTableCell(string(variable.Category)).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(fmt.Sprint(variable.HCL)).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(fmt.Sprint(variable.Sensitive)).SetExpansion(1))
+		table.SetCell(r, 5, tview.NewTableCell(variable.Description).SetExpansion(2))
+	}
+}
+
+// selected returns the variable in the selected row.
+func (v *VariablesPageSource) selected() *tfe.Variable {
+	if v.table == nil {
+		return nil
+	}
+
+	row, _ := v.table.GetSelection()
+	variable, _ := v.table.GetCell(row, 0).GetReference().(*tfe.Variable)
+	return variable
+}
+
+func (v *VariablesPageSource) BindKeys(l *ListPage) KeyActions {
+	return KeyActions{
+		KeyN: NewKeyAction("new variable", v.actionNewVariable, true),
+		KeyD: NewKeyAction("delete variable", v.actionDeleteVariable(l), true),
+		KeyX: NewKeyAction("export variables", v.actionExportVariables, true),
+		KeyI: NewKeyAction("import variables", v.actionImportVariables, true),
+	}
+}
+
+func (v *VariablesPageSource) actionNewVariable(ek *tcell.EventKey) *tcell.EventKey {
+	v.app.activatePage(VariablePageName, NewVariablePage(v.app, v.tfeClient, nil), false)
+	return nil
+}
+
+// actionDeleteVariable asks to confirm, typing the variable key, and deletes
+// the selected variable.
+func (v *VariablesPageSource) actionDeleteVariable(l *ListPage) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		variable := v.selected()
+		if variable == nil {
+			return nil
+		}
+
+		text := fmt.Sprintf("Delete the variable [::b]%s[::-] of the workspace [::b]%s[::-]?",
+			tview.Escape(variable.Key), tview.Escape(v.workspace.Name))
+		v.app.confirm("delete variable", text, variable.Key, func() {
+			ctx := v.app.pageCtx
+			workspaceID := v.workspace.ID
+			go func() {
+				err := v.tfeClient.DeleteVariable(ctx, workspaceID, variable.ID)
+
+				v.app.QueueUpdateDraw(func() {
+					if err != nil {
+						v.app.footer.ShowError(fmt.Sprintf("😵 error deleting the variable: %s", err))
+						return
+					}
+
+					v.app.footer.Show(fmt.Sprintf("✅ variable %s deleted", variable.Key), tview.Styles.SecondaryTextColor)
+					if ctx.Err() == nil {
+						v.app.reloadPage(l)
+					}
+				})
+			}()
+		})
+		return nil
+	}
+}
+
+// actionExportVariables asks for the file and exports the workspace variables
+// of the file format category, all pages.
+func (v *VariablesPageSource) actionExportVariables(ek *tcell.EventKey) *tcell.EventKey {
+	text := "The terraform variables are exported to a [::b].tfvars[::-] or [::b].tfvars.json[::-]\n" +
+		"file and the environment variables to a [::b].env[::-] file, with the\n" +
+		"sensitive values written as " + varfile.Placeholder + "."
+	v.app.prompt("export variables", text, "File", func(path string) {
+		format, err := varfile.FormatOf(path)
+		if err != nil {
+			v.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+			return
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			text := fmt.Sprintf("Overwrite the existing file?\n[::b]%s[::-]", tview.Escape(path))
+			v.app.confirm("overwrite file", text, "", func() {
+				v.exportTo(path, format, true)
+			})
+			return
+		}
+		v.exportTo(path, format, false)
+	})
+	return nil
+}
+
+func (v *VariablesPageSource) exportTo(path string, format varfile.Format, overwrite bool) {
+	ctx := v.app.pageCtx
+	workspaceID := v.workspace.ID
+	go func() {
+		n, err := exportVariables(ctx, v.tfeClient, workspaceID, path, format, overwrite)
+
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.footer.ShowError(fmt.Sprintf("😵 error exporting the variables: %s", err))
+				return
+			}
+			v.app.footer.Show(fmt.Sprintf("✅ %d variables exported to %s", n, path), tview.Styles.SecondaryTextColor)
+		})
+	}()
+}
+
+// exportVariables writes the variables to the file, failing if it exists
+// unless overwrite is set.
+func exportVariables(ctx context.Context, tfeClient client.TFEClient, workspaceID, path string, format varfile.Format, overwrite bool) (int, error) {
+	variables, err := allWorkspaceVariables(ctx, tfeClient, workspaceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var b bytes.Buffer
+	n, err := varfile.Write(&b, format, variables)
+	if err != nil {
+		return 0, err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		return 0, fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return n, nil
+}
+
+func (v *VariablesPageSource) actionImportVariables(ek *tcell.EventKey) *tcell.EventKey {
+	v.app.activatePage(VariablesImportPageName, nil, false)
+	return nil
+}
+
+// allWorkspaceVariables returns the workspace variables of all pages.
+func allWorkspaceVariables(ctx context.Context, tfeClient client.TFEClient, workspaceID string) ([]*tfe.Variable, error) {
+	variables := []*tfe.Variable{}
+	for page := 1; ; page++ {
+		list, err := tfeClient.ListWorkspaceVariables(ctx, workspaceID, page)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the workspace variables: %w", err)
+		}
+
+		variables = append(variables, list.Items...)
+		if list.Pagination == nil || list.CurrentPage >= list.TotalPages {
+			return variables, nil
+		}
+	}
+}
+
+func (v *VariablesPageSource) Crumb() []string {
+	return []string{
+		v.app.config.Organization,
+		v.app.config.Workspace,
+		VariablesPageName,
+	}
+}
+
+func (v *VariablesPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		variable := v.selected()
+		if variable == nil {
+			return nil
+		}
+
+		v.app.activatePage(VariablePageName, NewVariablePage(v.app, v.tfeClient, variable), false)
+		return nil
+	}
+}
+
+func (v *VariablesPageSource) Name() string {
+	return "variable"
+}
+
+func (v *VariablesPageSource) NameList() string {
+	return VariablesPageName
+}
+
+func (v *VariablesPageSource) Empty() bool {
+	return v.variables == nil || len(v.variables.Items) == 0
+}
+
+func (v *VariablesPageSource) CurrentPage() int {
+	return v.variables.CurrentPage
+}
+
+func (v *VariablesPageSource) TotalCount() int {
+	return v.variables.TotalCount
+}
+
+func (v *VariablesPageSource) TotalPages() int {
+	return v.variables.TotalPages
+}
@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+	"github.com/renato0307/terrui/internal/varfile"
+)
+
+const VariablesImportPageName string = "import variables"
+
+// VariablesImportPage imports the variables of a .tfvars, .tfvars.json or
+// .env file into the current workspace, previewing the creates, updates and
+// deletes before applying them.
+type VariablesImportPage struct {
+	*tview.Flex
+
+	app       *App
+	tfeClient client.TFEClient
+	workspace *tfe.Workspace
+	variables []*tfe.Variable
+
+	form    *tview.Form
+	preview *tview.TextView
+}
+
+func NewVariablesImportPage(app *App, tfeClient client.TFEClient) Page {
+	return &VariablesImportPage{
+		Flex:      tview.NewFlex(),
+		app:       app,
+		tfeClient: tfeClient,
+	}
+}
+
+func (v *VariablesImportPage) Load(ctx context.Context) error {
+	workspace, err := v.tfeClient.ReadWorkspace(ctx, v.app.config.Organization, v.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+	v.workspace = workspace
+
+	variables, err := allWorkspaceVariables(ctx, v.tfeClient, workspace.ID)
+	if err != nil {
+		return err
+	}
+	v.variables = variables
+
+	return nil
+}
+
+func (v *VariablesImportPage) View() string {
+	v.form = tview.NewForm()
+	v.preview = tview.NewTextView()
+
+	v.form.AddInputField("File", "", 0, nil, nil)
+	v.form.AddCheckbox("Delete missing", false, nil)
+	v.form.AddButton("Preview", v.showPreview)
+	v.form.AddButton("Import", v.confirmImport)
+	v.form.AddButton("Cancel", func() {
+		v.app.goBack(nil)
+	})
+	v.form.SetCancelFunc(func() {
+		v.app.goBack(nil)
+	})
+	v.form.SetBorder(true)
+	v.form.SetBorderPadding(1, 1, 1, 1)
+	v.form.SetTitle(fmt.Sprintf(" import variables into %s ", v.workspace.Name))
+
+	v.preview.SetBorder(true)
+	v.preview.SetBorderPadding(1, 1, 1, 1)
+	v.preview.SetTitle(" changes ")
+	v.preview.SetDynamicColors(true)
+	v.preview.SetText("[::d]the .tfvars and .tfvars.json files have terraform variables and the .env files environment variables[::-]")
+
+	v.Flex.Clear().
+		AddItem(v.form, 0, 1, true).
+		AddItem(v.preview, 0, 1, false)
+	v.app.SetFocus(v.form)
+
+	return "import variables"
+}
+
+// plan reads the file and returns the changes to import it.
+func (v *VariablesImportPage) plan() ([]*varfile.Change, error) {
+	path := strings.TrimSpace(v.form.GetFormItemByLabel("File").(*tview.InputField).GetText())
+	if path == "" {
+		return nil, errors.New("the file is required")
+	}
+
+	format, err := varfile.FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	variables, err := varfile.Parse(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	deleteMissing := v.form.GetFormItemByLabel("Delete missing").(*tview.Checkbox).IsChecked()
+	return varfile.Plan(v.variables, variables, format.Category(), deleteMissing), nil
+}
+
+func (v *VariablesImportPage) showPreview() {
+	changes, err := v.plan()
+	if err != nil {
+		v.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+		return
+	}
+	v.renderPreview(changes)
+}
+
+func (v *VariablesImportPage) renderPreview(changes []*varfile.Change) {
+	if len(changes) == 0 {
+		v.preview.SetText("[::d]no changes, the variables are up to date[::-]")
+		return
+	}
+
+	colors := map[varfile.Action]string{
+		varfile.ActionCreate: "green",
+		varfile.ActionUpdate: "yellow",
+		varfile.ActionDelete: "red",
+		varfile.ActionSkip:   "gray",
+	}
+	lines := []string{}
+	for _, c := range changes {
+		lines = append(lines, fmt.Sprintf("[%s]%s[-]", colors[c.Action], tview.Escape(c.String())))
+	}
+	v.preview.SetText(strings.Join(lines, "\n"))
+}
+
+// confirmImport previews the changes and asks to confirm them, typing the
+// workspace name if variables are deleted.
+func (v *VariablesImportPage) confirmImport() {
+	changes, err := v.plan()
+	if err != nil {
+		v.app.footer.ShowError(fmt.Sprintf("😵 %s", err))
+		return
+	}
+	v.renderPreview(changes)
+
+	counts := map[varfile.Action]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+	}
+	if len(changes) == counts[varfile.ActionSkip] {
+		v.app.footer.ShowError("😵 no changes to import")
+		return
+	}
+
+	text := fmt.Sprintf("Import the variables into the workspace [::b]%s[::-]?\n%d creates, %d updates and %d deletes.",
+		tview.Escape(v.workspace.Name),
+		counts[varfile.ActionCreate], counts[varfile.ActionUpdate], counts[varfile.ActionDelete])
+	expected := ""
+	if counts[varfile.ActionDelete] > 0 {
+		expected = v.workspace.Name
+	}
+	v.app.confirm("import variables", text, expected, func() {
+		v.importVariables(changes)
+	})
+}
+
+// importVariables applies the changes one at a time, stopping at the first
+// error.
+func (v *VariablesImportPage) importVariables(changes []*varfile.Change) {
+	ctx := v.app.pageCtx
+	workspaceID := v.workspace.ID
+
+	go func() {
+		imported := 0
+		var err error
+		for _, c := range changes {
+			switch c.Action {
+			case varfile.ActionCreate:
+				_, err = v.tfeClient.CreateVariable(ctx, workspaceID, c.Options)
+			case varfile.ActionUpdate:
+				_, err = v.tfeClient.UpdateVariable(ctx, workspaceID, c.Variable.ID, c.Options)
+			case varfile.ActionDelete:
+				err = v.tfeClient.DeleteVariable(ctx, workspaceID, c.Variable.ID)
+			default:
+				continue
+			}
+			if err != nil {
+				err = fmt.Errorf("error importing the variable %s: %w", c.Key, err)
+				break
+			}
+			imported++
+		}
+
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.footer.ShowError(fmt.Sprintf("😵 %s, %d changes imported", err, imported))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			v.app.goBack(nil)
+			if page, ok := v.app.currentPage.(RefreshablePage); ok {
+				v.app.reloadPage(page)
+			}
+			v.app.footer.Show(fmt.Sprintf("✅ %d variable changes imported", imported), tview.Styles.SecondaryTextColor)
+		})
+	}()
+}
+
+func (v *VariablesImportPage) BindKeys() KeyActions {
+	return KeyActions{}
+}
+
+func (v *VariablesImportPage) Crumb() []string {
+	return []string{
+		v.app.config.Organization,
+		v.app.config.Workspace,
+		VariablesPageName,
+		"import",
+	}
+}
+
+func (v *VariablesImportPage) Name() string {
+	return VariablesImportPageName
+}
+
+func (v *VariablesImportPage) Footer() string {
+	return ""
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestVariablesPageExport(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	h.pressRune('x')
+	h.waitFor("export variables")
+	h.typeText(path)
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("2 variables exported")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "db_password = \"<sensitive>\"\nregion      = \"eu-west-1\"\n", string(data))
+}
+
+func TestVariablesPageExportOverwrite(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	require.NoError(t, os.WriteFile(path, []byte("region = \"us-east-1\"\n"), 0600))
+
+	h.pressRune('x')
+	h.waitFor("export variables")
+	h.typeText(path)
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("Overwrite the existing file?")
+	h.pressKey(tcell.KeyEsc)
+	h.waitForGone("Overwrite the existing file?")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "region = \"us-east-1\"\n", string(data))
+
+	h.pressRune('x')
+	h.waitFor("export variables")
+	h.typeText(path)
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("Overwrite the existing file?")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("2 variables exported")
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "db_password = \"<sensitive>\"\nregion      = \"eu-west-1\"\n", string(data))
+}
+
+func TestVariablesPageImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	require.NoError(t, os.WriteFile(path, []byte(`region = "us-east-1"
+db_password = "<sensitive>"
+zones = ["a", "b"]
+`), 0600))
+
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	h.pressRune('i')
+	h.waitFor("import variables into app-prod")
+	h.typeText(path)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	// preview
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("~ update region")
+	h.waitFor("+ create zones")
+	assert.NotContains(t, h.text(), "db_password")
+
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("Import the variables into the workspace app-prod?")
+	h.waitFor("1 creates, 1 updates and 0 deletes.")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("2 variable changes imported")
+	h.waitFor("<variables>")
+
+	assert.Equal(t, "us-east-1", findVariable(c, "ws-prod", "region").Value)
+	assert.Equal(t, "hunter2", findVariable(c, "ws-prod", "db_password").Value)
+	zones := findVariable(c, "ws-prod", "zones")
+	require.NotNil(t, zones)
+	assert.True(t, zones.HCL)
+	assert.Equal(t, tfe.CategoryTerraform, zones.Category)
+}
+
+func TestVariablesPageImportDeleteMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	require.NoError(t, os.WriteFile(path, []byte("region = \"eu-west-1\"\n"), 0600))
+
+	c := newFakeClient()
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, c)
+	showVariables(h)
+
+	h.pressRune('i')
+	h.waitFor("import variables into app-prod")
+	h.typeText(path)
+	h.pressKey(tcell.KeyTab)
+	// delete missing
+	h.pressRune(' ')
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("0 creates, 0 updates and 1 deletes.")
+	h.typeText("app-prod")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("1 variable changes imported")
+	assert.Nil(t, findVariable(c, "ws-prod", "db_password"))
+	assert.NotNil(t, findVariable(c, "ws-prod", "region"))
+}
+
+func TestVariablesPageImportInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	require.NoError(t, os.WriteFile(path, []byte("region = \n"), 0600))
+
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, newFakeClient())
+	showVariables(h)
+
+	h.pressRune('i')
+	h.waitFor("import variables into app-prod")
+	h.typeText(path)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyTab)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("line 1: Invalid expression")
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+const (
+	VariableSetsPageName         string = "varsets"
+	VariableSetVariablesPageName string = "varset variables"
+)
+
+// VariableSetsPageSource lists the variable sets of the organization, showing
+// which are applied to the current workspace.
+type VariableSetsPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	workspace *tfe.Workspace
+	sets      *tfe.VariableSetList
+
+	table *tview.Table
+}
+
+func NewVariableSetsPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &VariableSetsPageSource{app: app, tfeClient: tfeClient})
+}
+
+func (v *VariableSetsPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (v *VariableSetsPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	workspace, err := v.tfeClient.ReadWorkspace(ctx, v.app.config.Organization, v.app.config.Workspace)
+	if err != nil {
+		return fmt.Errorf("error reading the workspace: %w", err)
+	}
+	v.workspace = workspace
+
+	sets, err := v.tfeClient.ListVariableSets(ctx, v.app.config.Organization, pageNumber)
+	if err != nil {
+		return fmt.Errorf("error reading the variable sets: %w", err)
+	}
+	v.sets = sets
+
+	return nil
+}
+
+func (v *VariableSetsPageSource) Columns() []Column {
+	return []Column{
+		{Name: "NAME"},
+		{Name: "SCOPE"},
+		{Name: "APPLIED"},
+		{Name: "VARIABLES"},
+		{Name: "DESCRIPTION"},
+	}
+}
+
+func (v *VariableSetsPageSource) RenderRows(table *tview.Table) {
+	v.table = table
+
+	for i, s := range v.sets.Items {
+		r := i + 1
+
+		scope := fmt.Sprintf("%d workspaces", len(s.Workspaces))
+		if s.Global {
+			scope = "global"
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(s.Name).SetExpansion(2).SetReference(s))
+		table.SetCell(r, 1, tview.NewTableCell(scope).SetExpansion(1))
+		table.SetCell(r, 2, tview.NewTableCell(fmt.Sprint(client.VariableSetApplied(s, v.workspace.ID))).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(fmt.Sprint(len(s.Variables))).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(s.Description).SetExpansion(3))
+	}
+}
+
+// selected returns the variable set in the selected row.
+func (v *VariableSetsPageSource) selected() *tfe.VariableSet {
+	if v.table == nil {
+		return nil
+	}
+
+	row, _ := v.table.GetSelection()
+	s, _ := v.table.GetCell(row, 0).GetReference().(*tfe.VariableSet)
+	return s
+}
+
+func (v *VariableSetsPageSource) Crumb() []string {
+	return []string{
+		v.app.config.Organization,
+		v.app.config.Workspace,
+		VariableSetsPageName,
+	}
+}
+
+func (v *VariableSetsPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		s := v.selected()
+		if s == nil {
+			return nil
+		}
+
+		v.app.activatePage(VariableSetVariablesPageName, NewVariableSetVariablesPage(v.app, v.tfeClient, s), false)
+		return nil
+	}
+}
+
+func (v *VariableSetsPageSource) Name() string {
+	return "variable set"
+}
+
+func (v *VariableSetsPageSource) NameList() string {
+	return VariableSetsPageName
+}
+
+func (v *VariableSetsPageSource) Empty() bool {
+	return v.sets == nil || len(v.sets.Items) == 0
+}
+
+func (v *VariableSetsPageSource) CurrentPage() int {
+	return v.sets.CurrentPage
+}
+
+func (v *VariableSetsPageSource) TotalCount() int {
+	return v.sets.TotalCount
+}
+
+func (v *VariableSetsPageSource) TotalPages() int {
+	return v.sets.TotalPages
+}
+
+// VariableSetVariablesPageSource lists the variables of a variable set.
+type VariableSetVariablesPageSource struct {
+	app       *App
+	tfeClient client.TFEClient
+	set       *tfe.VariableSet
+	variables *tfe.VariableSetVariableList
+}
+
+func NewVariableSetVariablesPage(app *App, tfeClient client.TFEClient, set *tfe.VariableSet) Page {
+	return NewListPage(app, &VariableSetVariablesPageSource{app: app, tfeClient: tfeClient, set: set})
+}
+
+func (v *VariableSetVariablesPageSource) SupportsSearch() bool {
+	return false
+}
+
+func (v *VariableSetVariablesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	variables, err := v.tfeClient.ListVariableSetVariables(ctx, v.set.ID, pageNumber)
+	if err != nil {
+		return fmt.Errorf("error reading the variables of the variable set: %w", err)
+	}
+	v.variables = variables
+
+	return nil
+}
+
+func (v *VariableSetVariablesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "KEY"},
+		{Name: "VALUE"},
+		{Name: "CATEGORY"},
+		{Name: "HCL"},
+		{Name: "SENSITIVE"},
+		{Name: "DESCRIPTION"},
+	}
+}
+
+func (v *VariableSetVariablesPageSource) RenderRows(table *tview.Table) {
+	for i, variable := range v.variables.Items {
+		r := i + 1
+
+		value := variable.Value
+		if variable.Sensitive {
+			value = "******"
+		}
+
+		table.SetCell(r, 0, tview.NewTableCell(variable.Key).SetExpansion(1))
+		table.SetCell(r, 1, tview.NewTableCell(value).SetExpansion(2))
+		table.SetCell(r, 2, tview.NewTableCell(string(variable.Category)).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(fmt.Sprint(variable.HCL)).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(fmt.Sprint(variable.Sensitive)).SetExpansion(1))
+		table.SetCell(r, 5, tview.NewTableCell(variable.Description).SetExpansion(2))
+	}
+}
+
+func (v *VariableSetVariablesPageSource) Crumb() []string {
+	return []string{
+		v.app.config.Organization,
+		v.app.config.Workspace,
+		VariableSetsPageName,
+		v.set.Name,
+	}
+}
+
+func (v *VariableSetVariablesPageSource) ActionSelectWorkspace(table *tview.Table, currentItem int) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		return nil
+	}
+}
+
+func (v *VariableSetVariablesPageSource) Name() string {
+	return "variable"
+}
+
+func (v *VariableSetVariablesPageSource) NameList() string {
+	return VariableSetVariablesPageName
+}
+
+func (v *VariableSetVariablesPageSource) Empty() bool {
+	return v.variables == nil || len(v.variables.Items) == 0
+}
+
+func (v *VariableSetVariablesPageSource) CurrentPage() int {
+	return v.variables.CurrentPage
+}
+
+func (v *VariableSetVariablesPageSource) TotalCount() int {
+	return v.variables.TotalCount
+}
+
+func (v *VariableSetVariablesPageSource) TotalPages() int {
+	return v.variables.TotalPages
+}
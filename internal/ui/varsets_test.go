@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+// newFakeClientWithVariableSets returns the fake client with a global variable
+// set, one applied to app-prod and one applied to app-dev.
+func newFakeClientWithVariableSets() *fake.Client {
+	c := newFakeClient()
+
+	global := fake.NewVariableSet("varset-aws", "aws-credentials", true)
+	global.Description = "AWS credentials"
+	secret := fake.NewVariableSetVariable("var-aws-secret", "AWS_SECRET_ACCESS_KEY", "s3cr3t")
+	secret.Category = tfe.CategoryEnv
+	secret.Sensitive = true
+	global.Variables = []*tfe.VariableSetVariable{secret}
+	c.AddVariableSet("acme", global)
+
+	prod := fake.NewVariableSet("varset-prod", "prod-defaults", false)
+	prod.Workspaces = []*tfe.Workspace{{ID: "ws-prod"}}
+	prod.Variables = []*tfe.VariableSetVariable{
+		fake.NewVariableSetVariable("var-prod-region", "region", "us-east-1"),
+		fake.NewVariableSetVariable("var-prod-instance", "instance_type", "t3.large"),
+	}
+	c.AddVariableSet("acme", prod)
+
+	dev := fake.NewVariableSet("varset-dev", "dev-defaults", false)
+	dev.Workspaces = []*tfe.Workspace{{ID: "ws-dev"}}
+	dev.Variables = []*tfe.VariableSetVariable{
+		fake.NewVariableSetVariable("var-dev-instance", "instance_type", "t3.micro"),
+	}
+	c.AddVariableSet("acme", dev)
+
+	return c
+}
+
+func TestVariableSetsPage(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, newFakeClientWithVariableSets())
+	h.waitFor("workspace details")
+
+	h.pressRune('v')
+	h.waitFor("page 1 of 1, total varsets: 3")
+	h.waitFor("AWS credentials")
+	h.waitFor("1 workspaces")
+	assert.Contains(t, h.text(), "global")
+
+	h.pressKey(tcell.KeyDown)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("<prod-defaults>")
+	h.waitFor("t3.large")
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("AWS credentials")
+}
+
+func TestEffectiveVariablesPage(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme", Workspace: "app-prod"}, newFakeClientWithVariableSets())
+	h.waitFor("workspace details")
+
+	h.pressRune(':')
+	h.typeText("effective")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("total effective variables: 5")
+	h.waitFor("overridden by workspace")
+	h.waitFor("prod-defaults (set)")
+	h.waitFor("aws-credentials (global set)")
+
+	text := h.text()
+	assert.Contains(t, text, "t3.large")
+	assert.NotContains(t, text, "t3.micro")
+	assert.NotContains(t, text, "hunter2")
+	assert.NotContains(t, text, "s3cr3t")
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -17,15 +18,39 @@ const WorkspacePageName string = "workspace"
 
 type WorkspacePage struct {
 	*tview.Flex
+	workspaceData
 
-	app           *App
-	workspace     *tfe.Workspace
-	variables     *tfe.VariableList
-	runs          *tfe.RunList
-	accesses      *tfe.TeamAccessList
+	app       *App
+	tfeClient client.TFEClient
+	// refreshed is the data loaded by the auto refresh, shown by Repaint
+	refreshed     *workspaceData
 	selectedRunID string
+	revealOutputs bool
+	// sensitiveValues are the values of the sensitive outputs read, by
+	// output ID, and readingOutputs the ones being read
+	sensitiveValues map[string]interface{}
+	readingOutputs  map[string]bool
+
+	sections      []*tview.Box
+	details       *tview.TextView
+	lastRun       *tview.TextView
+	tagsList      *tview.List
+	accessesList  *tview.List
+	runsList      *tview.List
+	variablesList *tview.List
+	outputsList   *tview.List
+	outputValue   *tview.TextView
+}
 
-	sections []*tview.Box
+// workspaceData is what the workspace page shows.
+type workspaceData struct {
+	workspace  *tfe.Workspace
+	variables  *tfe.VariableList
+	runs       *tfe.RunList
+	accesses   *tfe.TeamAccessList
+	outputs    []*tfe.StateVersionOutput
+	outputsErr error
+	lock       *client.WorkspaceLock
 }
 
 type workspaceBaseInfo struct {
@@ -36,6 +61,7 @@ type workspaceBaseInfo struct {
 	TerraformVersion string `yaml:"Terraform Version"`
 	Updated          string `yaml:"Updated"`
 	Locked           bool   `yaml:"Locked"`
+	LockedBy         string `yaml:"Locked By,omitempty"`
 	WorkingDirectory string `yaml:"Working Directory"`
 	ExecutionMode    string `yaml:"Execution Mode"`
 	AutoApply        bool   `yaml:"Auto Apply"`
@@ -55,85 +81,85 @@ type workspaceMetrics struct {
 	RunFailures          int    `yaml:"Total Failed Runs"`
 }
 
-func NewWorkspacePage(app *App) Page {
+func NewWorkspacePage(app *App, tfeClient client.TFEClient) Page {
 	ol := WorkspacePage{
-		Flex: tview.NewFlex(),
-		app:  app,
+		Flex:            tview.NewFlex(),
+		app:             app,
+		tfeClient:       tfeClient,
+		sensitiveValues: map[string]interface{}{},
+		readingOutputs:  map[string]bool{},
 	}
 
 	return &ol
 }
 
-func (w *WorkspacePage) Load() error {
-	tfeClient, err := client.NewTFEClient()
+func (w *WorkspacePage) Load(ctx context.Context) error {
+	data, err := w.load(ctx)
 	if err != nil {
-		return fmt.Errorf("error creating the TFE client: %w", err)
+		return err
 	}
+	w.workspaceData = *data
+
+	return nil
+}
+
+// load reads the workspace data, without changing the page as the auto
+// refresh calls it in the background.
+func (w *WorkspacePage) load(ctx context.Context) (*workspaceData, error) {
+	data := &workspaceData{}
 
-	workspace, err := tfeClient.ReadWorkspace(w.app.config.Organization, w.app.config.Workspace)
+	workspace, err := w.tfeClient.ReadWorkspace(ctx, w.app.config.Organization, w.app.config.Workspace)
 	if err != nil {
-		return fmt.Errorf("error reading the workspace: %w", err)
+		return nil, fmt.Errorf("error reading the workspace: %w", err)
 	}
-	w.workspace = workspace
+	data.workspace = workspace
 
-	vars, err := tfeClient.ListWorkspaceVariables(workspace.ID)
+	vars, err := w.tfeClient.ListWorkspaceVariables(ctx, workspace.ID, -1)
 	if err != nil {
-		return fmt.Errorf("error reading the workspace variables: %w", err)
+		return nil, fmt.Errorf("error reading the workspace variables: %w", err)
 	}
-	w.variables = vars
+	data.variables = vars
 
-	runs, err := tfeClient.ListWorkspaceRuns(workspace.ID)
+	runs, err := w.tfeClient.ListWorkspaceRuns(ctx, workspace.ID)
 	if err != nil {
-		return fmt.Errorf("error reading the workspace variables: %w", err)
+		return nil, fmt.Errorf("error reading the workspace variables: %w", err)
 	}
-	w.runs = runs
+	data.runs = runs
 
-	accesses, err := tfeClient.ListWorkspaceTeamAccesses(workspace.ID)
+	accesses, err := w.tfeClient.ListWorkspaceTeamAccesses(ctx, workspace.ID)
 	if err != nil {
-		return fmt.Errorf("error reading the workspace accesses: %w", err)
+		return nil, fmt.Errorf("error reading the workspace accesses: %w", err)
 	}
-	w.accesses = accesses
+	data.accesses = accesses
 
-	return nil
+	// the outputs need permission to read the state, so an error is shown
+	// in the outputs panel instead of failing the page
+	outputs, err := w.tfeClient.ListWorkspaceOutputs(ctx, workspace.ID)
+	if err != nil {
+		data.outputsErr = fmt.Errorf("error reading the workspace outputs: %w", err)
+	}
+	data.outputs = outputs
+
+	if workspace.Locked {
+		lock, err := w.tfeClient.ReadWorkspaceLock(ctx, workspace.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the workspace lock: %w", err)
+		}
+		data.lock = lock
+	}
+
+	return data, nil
 }
 
 func (w *WorkspacePage) View() string {
 	workspace := w.workspace
 	w.sections = []*tview.Box{}
 
-	workspaceBase := workspaceBaseInfo{
-		ID:               workspace.ID,
-		Name:             workspace.Name,
-		Description:      workspace.Description,
-		TerraformVersion: workspace.TerraformVersion,
-		Resources:        workspace.ResourceCount,
-		Updated:          fmtTime(workspace.UpdatedAt),
-		Locked:           workspace.Locked,
-		WorkingDirectory: workspace.WorkingDirectory,
-		ExecutionMode:    workspace.ExecutionMode,
-		AutoApply:        workspace.AutoApply,
-	}
-
-	wLastRun := workspaceLastRun{}
-	if workspace.CurrentRun != nil {
-		if workspace.CurrentRun.CreatedBy != nil {
-			wLastRun.By = workspace.CurrentRun.CreatedBy.Username
-			wLastRun.When = fmtTime(workspace.CurrentRun.CreatedAt)
-		}
-		wLastRun.Status = string(workspace.CurrentRun.Status)
-		wLastRun.ResourcesAdded = workspace.CurrentRun.Plan.ResourceAdditions
-		wLastRun.ResourcesUpdated = workspace.CurrentRun.Plan.ResourceChanges
-		wLastRun.ResourcesDeleted = workspace.CurrentRun.Plan.ResourceDestructions
-	}
-
 	workspaceMetrics := workspaceMetrics{
 		ApplyDurationAverage: workspace.ApplyDurationAverage.String(),
 		PlanDurationAverage:  workspace.PlanDurationAverage.String(),
 		RunFailures:          workspace.RunFailures,
 	}
-
-	yamlBaseData, _ := yaml.Marshal(workspaceBase)
-	yamlLastRunData, _ := yaml.Marshal(wLastRun)
 	yamlMetrics, _ := yaml.Marshal(workspaceMetrics)
 
 	details := tview.NewTextView()
@@ -143,11 +169,13 @@ func (w *WorkspacePage) View() string {
 	accesses := tview.NewList()
 	variables := tview.NewList()
 	runs := tview.NewList()
+	outputs := tview.NewList()
+	outputValue := tview.NewTextView()
 
 	details.SetBorder(true)
 	details.SetBorderPadding(0, 1, 1, 1)
 	details.SetTitle(" workspace details ")
-	details.SetText(colorizeYAML(string(yamlBaseData)))
+	details.SetText(w.detailsText())
 	details.SetDynamicColors(true)
 	w.sections = append(w.sections, details.Box)
 
@@ -159,9 +187,6 @@ func (w *WorkspacePage) View() string {
 	tags.SetWrapAround(true)
 	tags.SetSelectedFocusOnly(true)
 	tags.ShowSecondaryText(false)
-	for _, t := range workspace.TagNames {
-		tags.AddItem(t, "", 0, nil)
-	}
 	w.sections = append(w.sections, tags.Box)
 
 	accesses.SetBorder(true)
@@ -172,9 +197,6 @@ func (w *WorkspacePage) View() string {
 	accesses.SetWrapAround(true)
 	accesses.SetSelectedFocusOnly(true)
 	accesses.ShowSecondaryText(true)
-	for _, a := range w.accesses.Items {
-		accesses.AddItem(a.Team.Name, string(a.Access), 0, nil)
-	}
 	w.sections = append(w.sections, accesses.Box)
 
 	metrics.SetBorder(true)
@@ -186,7 +208,7 @@ func (w *WorkspacePage) View() string {
 	lastRun.SetBorder(true)
 	lastRun.SetBorderPadding(0, 1, 1, 1)
 	lastRun.SetTitle(" last run ")
-	lastRun.SetText(colorizeYAML(string(yamlLastRunData)))
+	lastRun.SetText(w.lastRunText())
 	lastRun.SetDynamicColors(true)
 
 	variables.SetBorder(true)
@@ -200,7 +222,7 @@ func (w *WorkspacePage) View() string {
 	variables.SetFocusFunc(func() {
 		w.app.actions.Add(
 			KeyActions{
-				tcell.KeyEnter: NewKeyAction("select run", w.actionShowVariable, true),
+				tcell.KeyEnter: NewKeyAction("edit variable", w.actionShowVariable, true),
 			},
 		)
 	})
@@ -232,6 +254,35 @@ func (w *WorkspacePage) View() string {
 	})
 	w.sections = append(w.sections, runs.Box)
 
+	outputs.SetBorder(true)
+	outputs.SetBorderPadding(0, 1, 1, 1)
+	outputs.SetSelectedFocusOnly(true)
+	outputs.SetMainTextStyle(tcell.StyleDefault.Bold(true))
+	outputs.SetSecondaryTextStyle(tcell.StyleDefault.Dim(true))
+	outputs.SetHighlightFullLine(true)
+	outputs.SetWrapAround(true)
+	outputs.ShowSecondaryText(false)
+	outputs.SetTitle(" outputs ")
+	outputs.SetFocusFunc(func() {
+		w.app.actions.Add(
+			KeyActions{
+				KeyC: NewKeyAction("copy output value", w.actionCopyOutput, true),
+			},
+		)
+	})
+	outputs.SetBlurFunc(func() {
+		w.app.actions.Delete(KeyC)
+	})
+	outputs.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		w.showOutputValue(index)
+	})
+	w.sections = append(w.sections, outputs.Box)
+
+	outputValue.SetBorder(true)
+	outputValue.SetBorderPadding(0, 1, 1, 1)
+	outputValue.SetTitle(" output value ")
+	outputValue.SetDynamicColors(true)
+
 	flex := tview.NewFlex().
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(details, 0, 2, false).
@@ -240,7 +291,10 @@ func (w *WorkspacePage) View() string {
 				AddItem(accesses, 0, 1, false), 0, 1, false).
 			AddItem(tview.NewFlex().
 				AddItem(lastRun, 0, 1, false).
-				AddItem(metrics, 0, 1, false), 0, 1, false), 0, 1, false)
+				AddItem(metrics, 0, 1, false), 0, 1, false).
+			AddItem(tview.NewFlex().
+				AddItem(outputs, 0, 1, false).
+				AddItem(outputValue, 0, 1, false), 0, 1, false), 0, 1, false)
 
 	if w.app.config.WorkspaceShowVariables {
 		flex.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
@@ -254,13 +308,129 @@ func (w *WorkspacePage) View() string {
 		return x, y, width, height
 	})
 	w.Flex = flex
-
+	w.details = details
+	w.lastRun = lastRun
+	w.tagsList = tags
+	w.accessesList = accesses
+	w.runsList = runs
+	w.variablesList = variables
+	w.outputsList = outputs
+	w.outputValue = outputValue
+
+	w.showTags(tags)
+	w.showAccesses(accesses)
 	w.showVariables(variables, true)
 	w.showRuns(runs, true)
+	w.showOutputs(outputs)
+	w.showOutputValue(0)
 
 	return "workspace loaded"
 }
 
+func (w *WorkspacePage) detailsText() string {
+	workspace := w.workspace
+	workspaceBase := workspaceBaseInfo{
+		ID:               workspace.ID,
+		Name:             workspace.Name,
+		Description:      workspace.Description,
+		TerraformVersion: workspace.TerraformVersion,
+		Resources:        workspace.ResourceCount,
+		Updated:          fmtTime(workspace.UpdatedAt),
+		Locked:           workspace.Locked,
+		LockedBy:         w.lockHolder(),
+		WorkingDirectory: workspace.WorkingDirectory,
+		ExecutionMode:    workspace.ExecutionMode,
+		AutoApply:        workspace.AutoApply,
+	}
+
+	yamlBaseData, _ := yaml.Marshal(workspaceBase)
+	return colorizeYAML(string(yamlBaseData))
+}
+
+func (w *WorkspacePage) lastRunText() string {
+	workspace := w.workspace
+	wLastRun := workspaceLastRun{}
+	if workspace.CurrentRun != nil {
+		if workspace.CurrentRun.CreatedBy != nil {
+			wLastRun.By = workspace.CurrentRun.CreatedBy.Username
+			wLastRun.When = fmtTime(workspace.CurrentRun.CreatedAt)
+		}
+		wLastRun.Status = string(workspace.CurrentRun.Status)
+		wLastRun.ResourcesAdded = workspace.CurrentRun.Plan.ResourceAdditions
+		wLastRun.ResourcesUpdated = workspace.CurrentRun.Plan.ResourceChanges
+		wLastRun.ResourcesDeleted = workspace.CurrentRun.Plan.ResourceDestructions
+	}
+
+	yamlLastRunData, _ := yaml.Marshal(wLastRun)
+	return colorizeYAML(string(yamlLastRunData))
+}
+
+func (w *WorkspacePage) RefreshFunc() func(context.Context) error {
+	return func(ctx context.Context) error {
+		data, err := w.load(ctx)
+		if err != nil {
+			return err
+		}
+		w.refreshed = data
+		return nil
+	}
+}
+
+// Repaint shows the refreshed data, keeping the selected items of the lists.
+func (w *WorkspacePage) Repaint() {
+	if w.refreshed != nil {
+		w.workspaceData = *w.refreshed
+		w.refreshed = nil
+	}
+
+	w.details.SetText(w.detailsText())
+	w.lastRun.SetText(w.lastRunText())
+
+	repaintList(w.tagsList, w.showTags)
+	repaintList(w.accessesList, w.showAccesses)
+	repaintList(w.variablesList, func(list *tview.List) {
+		w.showVariables(list, true)
+	})
+	repaintList(w.runsList, func(list *tview.List) {
+		w.showRuns(list, true)
+	})
+	repaintList(w.outputsList, w.showOutputs)
+	w.showOutputValue(w.outputsList.GetCurrentItem())
+}
+
+// repaintList fills the list again, keeping the selected item.
+func repaintList(list *tview.List, show func(list *tview.List)) {
+	current := list.GetCurrentItem()
+	list.Clear()
+	show(list)
+	list.SetCurrentItem(current)
+}
+
+// Settled is true when the current run and all the listed runs are finished.
+func (w *WorkspacePage) Settled() bool {
+	if !runSettled(w.workspace.CurrentRun) {
+		return false
+	}
+	for _, r := range w.runs.Items {
+		if !runSettled(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *WorkspacePage) showTags(list *tview.List) {
+	for _, t := range w.workspace.TagNames {
+		list.AddItem(t, "", 0, nil)
+	}
+}
+
+func (w *WorkspacePage) showAccesses(list *tview.List) {
+	for _, a := range w.accesses.Items {
+		list.AddItem(a.Team.Name, string(a.Access), 0, nil)
+	}
+}
+
 func (w *WorkspacePage) showVariables(list *tview.List, showShortcuts bool) {
 	max := 10
 	shortcut := int('0')
@@ -312,7 +482,7 @@ func (w *WorkspacePage) showRuns(list *tview.List, showShortcuts bool) {
 			iconStatus = "❌ "
 		case tfe.RunApplied, tfe.RunPlannedAndFinished:
 			iconStatus = "✅	 "
-		case tfe.RunPlanned, tfe.RunPlanning:
+		case tfe.RunPlanned, tfe.RunPlanning, tfe.RunApplying, tfe.RunApplyQueued, tfe.RunConfirmed:
 			iconStatus = "⏳ "
 		case tfe.RunDiscarded:
 			iconStatus = "🌪 "
@@ -353,11 +523,37 @@ func (w *WorkspacePage) showRuns(list *tview.List, showShortcuts bool) {
 
 func (w *WorkspacePage) BindKeys() KeyActions {
 	return KeyActions{
-		tcell.KeyCtrlL: NewKeyAction("list workspaces", w.actionListWorkspaces, true),
-		tcell.KeyTab:   NewKeyAction("focus variables and run lists", w.actionFocusNextList, true),
+		tcell.KeyTab: NewKeyAction("focus variables and run lists", w.actionFocusNextList, true),
+		KeyShiftS:    NewKeyAction("state versions", w.actionShowStateVersions, true),
+		KeyV:         NewKeyAction("variable sets", w.actionShowPage(VariableSetsPageName), true),
+		KeyE:         NewKeyAction("effective variables", w.actionShowPage(EffectiveVariablesPageName), true),
+		KeyR:         NewKeyAction("reveal or mask sensitive outputs", w.actionToggleRevealOutputs, true),
+		KeyN:         NewKeyAction("queue plan and apply run", w.actionQueueRun(client.RunOptions{}), true),
+		KeyShiftD:    NewKeyAction("queue destroy run", w.actionQueueRun(client.RunOptions{IsDestroy: true}), true),
+		KeyShiftN:    NewKeyAction("queue run with options", w.actionShowRunBuilder, true),
+		KeyL:         NewKeyAction("lock workspace", w.actionLockWorkspace, true),
+		KeyU:         NewKeyAction("unlock workspace", w.actionUnlockWorkspace, true),
+		KeyShiftU:    NewKeyAction("force unlock workspace", w.actionForceUnlockWorkspace, true),
 	}
 }
 
+func (w *WorkspacePage) actionShowRunBuilder(ek *tcell.EventKey) *tcell.EventKey {
+	w.app.activatePage(RunBuilderPageName, nil, false)
+	return nil
+}
+
+func (w *WorkspacePage) actionShowPage(name string) func(ek *tcell.EventKey) *tcell.EventKey {
+	return func(ek *tcell.EventKey) *tcell.EventKey {
+		w.app.activatePage(name, nil, false)
+		return nil
+	}
+}
+
+func (w *WorkspacePage) actionShowStateVersions(ek *tcell.EventKey) *tcell.EventKey {
+	w.app.activatePage(StateVersionsPageName, nil, false)
+	return nil
+}
+
 func (w *WorkspacePage) Crumb() []string {
 	return []string{
 		w.app.config.Organization,
@@ -373,15 +569,6 @@ func (w *WorkspacePage) Footer() string {
 	return ""
 }
 
-func (w *WorkspacePage) actionListWorkspaces(ek *tcell.EventKey) *tcell.EventKey {
-	w.app.config.Workspace = ""
-	w.app.config.Save()
-
-	w.app.activatePage(WorkspacesPageName, nil, false)
-
-	return nil
-}
-
 func (w *WorkspacePage) actionFocusNextList(ek *tcell.EventKey) *tcell.EventKey {
 	for i, b := range w.sections {
 		if !b.HasFocus() {
@@ -412,7 +599,13 @@ func (w *WorkspacePage) actionShowRun(ek *tcell.EventKey) *tcell.EventKey {
 }
 
 func (w *WorkspacePage) actionShowVariable(ek *tcell.EventKey) *tcell.EventKey {
-	w.app.footer.Show("loading run", tview.Styles.PrimaryTextColor)
+	index := w.variablesList.GetCurrentItem()
+	if index < 0 || index >= len(w.variables.Items) {
+		return nil
+	}
+
+	variable := w.variables.Items[index]
+	w.app.activatePage(VariablePageName, NewVariablePage(w.app, w.tfeClient, variable), false)
 	return nil
 }
 
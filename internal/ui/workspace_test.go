@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestWorkspacePage(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod", WorkspaceShowVariables: true}
+	h := newHarness(t, cfg, newFakeClient())
+
+	h.waitFor("workspace details")
+	h.waitFor("Resource Count: 12")
+	h.waitFor("owners")
+	h.waitFor("region = eu-west-1")
+	h.waitFor("db_password = ******")
+	h.waitFor("add bucket » ❌ errored")
+}
+
+func TestWorkspacePageShowRun(t *testing.T) {
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod", WorkspaceShowVariables: true}
+	h := newHarness(t, cfg, newFakeClient())
+	h.waitFor("add bucket")
+
+	// details, tags, accesses, variables and then the runs
+	for i := 0; i < 5; i++ {
+		h.pressKey(tcell.KeyTab)
+	}
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("run details")
+	assert.Equal(t, "run-new", h.config.RunID)
+}
+
+func TestWorkspacePageError(t *testing.T) {
+	c := newFakeClient()
+	c.FailWith("ReadWorkspace", errors.New("boom"))
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+
+	h.waitFor("error reading the workspace: boom")
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/go-tfe"
+	"github.com/rivo/tview"
+)
+
+// lockHolder returns the holder of the workspace lock, empty if the workspace
+// is not locked.
+func (w *WorkspacePage) lockHolder() string {
+	if !w.workspace.Locked {
+		return ""
+	}
+	if w.lock == nil {
+		return "unknown"
+	}
+	return w.lock.Holder()
+}
+
+// canLock returns if the permission is granted, the permissions are not
+// always returned by the API and then the API decides.
+func (w *WorkspacePage) canLock(allowed func(*tfe.WorkspacePermissions) bool) bool {
+	return w.workspace.Permissions == nil || allowed(w.workspace.Permissions)
+}
+
+// actionLockWorkspace asks for the reason and locks the workspace.
+func (w *WorkspacePage) actionLockWorkspace(ek *tcell.EventKey) *tcell.EventKey {
+	workspace := w.workspace
+	if workspace == nil {
+		return nil
+	}
+	if workspace.Locked {
+		w.app.footer.ShowError(fmt.Sprintf("😵 the workspace is already locked by %s", w.lockHolder()))
+		return nil
+	}
+	if !w.canLock(func(p *tfe.WorkspacePermissions) bool { return p.CanLock }) {
+		w.app.footer.ShowError("😵 not allowed to lock the workspace")
+		return nil
+	}
+
+	text := fmt.Sprintf("Lock the workspace [::b]%s[::-]? No runs are started until it is unlocked.",
+		tview.Escape(workspace.Name))
+	w.app.prompt("lock workspace", text, "reason", func(reason string) {
+		w.changeLock("lock", "locked", func(ctx context.Context) error {
+			return w.tfeClient.LockWorkspace(ctx, workspace.ID, reason)
+		})
+	})
+	return nil
+}
+
+// actionUnlockWorkspace asks to confirm and unlocks a workspace locked by the
+// current user.
+func (w *WorkspacePage) actionUnlockWorkspace(ek *tcell.EventKey) *tcell.EventKey {
+	workspace := w.workspace
+	if workspace == nil {
+		return nil
+	}
+	if !workspace.Locked {
+		w.app.footer.ShowError("😵 the workspace is not locked")
+		return nil
+	}
+	if !w.canLock(func(p *tfe.WorkspacePermissions) bool { return p.CanUnlock }) {
+		w.app.footer.ShowError("😵 not allowed to unlock the workspace")
+		return nil
+	}
+
+	text := fmt.Sprintf("Unlock the workspace [::b]%s[::-] locked by [::b]%s[::-]?",
+		tview.Escape(workspace.Name), tview.Escape(w.lockHolder()))
+	w.app.confirm("unlock workspace", text, "", func() {
+		w.changeLock("unlock", "unlocked", func(ctx context.Context) error {
+			return w.tfeClient.UnlockWorkspace(ctx, workspace.ID)
+		})
+	})
+	return nil
+}
+
+// actionForceUnlockWorkspace asks to confirm, typing the workspace name, and
+// unlocks a workspace locked by another user.
+func (w *WorkspacePage) actionForceUnlockWorkspace(ek *tcell.EventKey) *tcell.EventKey {
+	workspace := w.workspace
+	if workspace == nil {
+		return nil
+	}
+	if !workspace.Locked {
+		w.app.footer.ShowError("😵 the workspace is not locked")
+		return nil
+	}
+	if !w.canLock(func(p *tfe.WorkspacePermissions) bool { return p.CanForceUnlock }) {
+		w.app.footer.ShowError("😵 not allowed to force unlock the workspace")
+		return nil
+	}
+
+	text := fmt.Sprintf("Force unlock the workspace [::b]%s[::-] locked by [::b]%s[::-]?\nThe holder of the lock may be changing the state.",
+		tview.Escape(workspace.Name), tview.Escape(w.lockHolder()))
+	w.app.confirm("force unlock workspace", text, workspace.Name, func() {
+		w.changeLock("force unlock", "force unlocked", func(ctx context.Context) error {
+			return w.tfeClient.ForceUnlockWorkspace(ctx, workspace.ID)
+		})
+	})
+	return nil
+}
+
+// changeLock requests the lock change, refreshing the page once done.
+func (w *WorkspacePage) changeLock(verb, done string, do func(ctx context.Context) error) {
+	ctx := w.app.pageCtx
+	name := w.workspace.Name
+
+	go func() {
+		err := do(ctx)
+
+		w.app.QueueUpdateDraw(func() {
+			if err != nil {
+				w.app.footer.ShowError(fmt.Sprintf("😵 error trying to %s the workspace: %s", verb, err))
+				return
+			}
+
+			w.app.footer.Show(fmt.Sprintf("✅ %s: %s", name, done), tview.Styles.SecondaryTextColor)
+			if ctx.Err() == nil {
+				w.app.reloadPage(w)
+			}
+		})
+	}()
+}
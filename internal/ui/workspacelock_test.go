@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func workspaceLocked(c *fake.Client, workspaceID string) bool {
+	lock, _ := c.ReadWorkspaceLock(context.Background(), workspaceID)
+	return lock != nil
+}
+
+func TestWorkspacePageLock(t *testing.T) {
+	c := newFakeClient()
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("workspace details")
+
+	h.pressRune('u')
+	h.waitFor("the workspace is not locked")
+
+	h.pressRune('l')
+	h.waitFor("Lock the workspace app-prod?")
+	h.typeText("incident 42")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("app-prod: locked")
+	h.waitFor("Locked By: jdoe (user)")
+	assert.Equal(t, "incident 42", c.LockReason("ws-prod"))
+
+	h.pressRune('l')
+	h.waitFor("the workspace is already locked by jdoe (user)")
+
+	h.pressRune('u')
+	h.waitFor("Unlock the workspace app-prod locked by jdoe (user)?")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("app-prod: unlocked")
+	h.waitForGone("Locked By")
+	assert.False(t, workspaceLocked(c, "ws-prod"))
+}
+
+func TestWorkspacePageForceUnlock(t *testing.T) {
+	c := newFakeClient()
+	c.SetWorkspaceLock("ws-prod", fake.NewUserLock("asmith"))
+
+	cfg := &config.Config{Organization: "acme", Workspace: "app-prod"}
+	h := newHarness(t, cfg, c)
+	h.waitFor("Locked By: asmith (user)")
+
+	// only the holder can unlock it
+	h.pressRune('u')
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("error trying to unlock the workspace: workspace locked by another user")
+
+	h.pressRune('U')
+	h.waitFor("Force unlock the workspace app-prod locked by asmith (user)?")
+	h.typeText("app-prod")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("app-prod: force unlocked")
+	h.waitUntil(func() bool {
+		return !workspaceLocked(c, "ws-prod")
+	}, "the workspace was not unlocked")
+}
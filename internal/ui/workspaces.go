@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,24 +18,24 @@ const WorkspacesPageName string = "workspaces"
 
 type WorkspacesPageSource struct {
 	app        *App
+	tfeClient  client.TFEClient
 	workspaces *tfe.WorkspaceList
 }
 
-func NewWorkspacesPage(app *App) Page {
-	return NewListPage(app, &WorkspacesPageSource{app: app})
+func NewWorkspacesPage(app *App, tfeClient client.TFEClient) Page {
+	return NewListPage(app, &WorkspacesPageSource{app: app, tfeClient: tfeClient})
 }
 
 func (w *WorkspacesPageSource) SupportsSearch() bool {
 	return true
 }
 
-func (w *WorkspacesPageSource) Search(searchText string, pageNumber int) error {
-	tfeClient, err := client.NewTFEClient()
-	if err != nil {
-		return fmt.Errorf("error creating the TFE client: %w", err)
+func (w *WorkspacesPageSource) Search(ctx context.Context, searchText string, pageNumber int) error {
+	workspaces, err := w.tfeClient.ListWorkspaces(ctx, w.app.config.Organization, searchText, pageNumber)
+	var queryErr *client.QueryError
+	if errors.As(err, &queryErr) {
+		return fmt.Errorf("invalid search: %w", err)
 	}
-
-	workspaces, err := tfeClient.ListWorkspaces(w.app.config.Organization, searchText, pageNumber)
 	if err != nil {
 		return fmt.Errorf("error listing the workspaces: %w", err)
 	}
@@ -42,14 +44,16 @@ func (w *WorkspacesPageSource) Search(searchText string, pageNumber int) error {
 	return nil
 }
 
-func (w *WorkspacesPageSource) RenderHeader(table *tview.Table) {
-	table.SetCell(0, 0, tview.NewTableCell("ID").SetSelectable(false))
-	table.SetCell(0, 1, tview.NewTableCell("NAME").SetSelectable(false))
-	table.SetCell(0, 2, tview.NewTableCell("TAGS").SetSelectable(false))
-	table.SetCell(0, 3, tview.NewTableCell("TERRAFORM").SetSelectable(false))
-	table.SetCell(0, 4, tview.NewTableCell("COUNT").SetSelectable(false))
-	table.SetCell(0, 5, tview.NewTableCell("RUN STATUS").SetSelectable(false))
-	table.SetCell(0, 6, tview.NewTableCell("LATEST CHANGE").SetSelectable(false))
+func (w *WorkspacesPageSource) Columns() []Column {
+	return []Column{
+		{Name: "ID"},
+		{Name: "NAME"},
+		{Name: "TAGS"},
+		{Name: "TERRAFORM"},
+		{Name: "COUNT"},
+		{Name: "RUN STATUS"},
+		{Name: "LATEST CHANGE"},
+	}
 }
 
 func (w *WorkspacesPageSource) RenderRows(table *tview.Table) {
@@ -71,7 +75,7 @@ func fmtTags(w *tfe.Workspace) *tview.TableCell {
 }
 
 func fmtUpdatedAt(w *tfe.Workspace) *tview.TableCell {
-	return tview.NewTableCell(humanize.Time(w.UpdatedAt.Local()))
+	return tview.NewTableCell(humanize.Time(w.UpdatedAt.Local())).SetReference(w.UpdatedAt)
 }
 
 func fmtCurrentRun(w *tfe.Workspace) *tview.TableCell {
@@ -90,7 +94,7 @@ func fmtCurrentRun(w *tfe.Workspace) *tview.TableCell {
 	case tfe.RunApplied, tfe.RunPlannedAndFinished:
 		style = style.Bold(true)
 		style = style.Foreground(tcell.ColorGreen)
-	case tfe.RunPlanned, tfe.RunPlanning:
+	case tfe.RunPlanned, tfe.RunPlanning, tfe.RunApplying, tfe.RunApplyQueued, tfe.RunConfirmed:
 		style = style.Bold(true)
 		style = style.Foreground(tcell.ColorYellow)
 	default:
@@ -124,6 +128,17 @@ func (w *WorkspacesPageSource) ActionSelectWorkspace(table *tview.Table, current
 	}
 }
 
+// Settled is true when the current run of all the listed workspaces is
+// finished.
+func (w *WorkspacesPageSource) Settled() bool {
+	for _, wi := range w.workspaces.Items {
+		if !runSettled(wi.CurrentRun) {
+			return false
+		}
+	}
+	return true
+}
+
 func (w *WorkspacesPageSource) Empty() bool {
 	return w.workspaces == nil || len(w.workspaces.Items) == 0
 }
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/renato0307/terrui/internal/client/fake"
+	"github.com/renato0307/terrui/internal/config"
+)
+
+func TestWorkspacesPage(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+
+	h.waitFor("app-dev")
+	h.waitFor("prod app")
+
+	// the first row is selected, so its colors are reversed
+	fg, _, _ := h.styleOf("applied").Decompose()
+	assert.Equal(t, tcell.ColorGreen, fg)
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-prod", h.config.Workspace)
+}
+
+func TestWorkspacesPageSearch(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('/')
+	h.typeText("dev")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitForGone("app-prod")
+	h.waitFor("app-dev")
+}
+
+func TestWorkspacesPageSearchQuery(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('/')
+	h.typeText("app -tag:prod status:applied")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitForGone("app-prod")
+	h.waitFor("app-dev")
+}
+
+func TestWorkspacesPageSearchInvalidQuery(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('/')
+	h.typeText("mode:cloud")
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("invalid search: unknown execution mode cloud")
+}
+
+func TestWorkspacesPageSort(t *testing.T) {
+	c := newFakeClient()
+	c.AddWorkspace("acme", fake.NewWorkspace("ws-web", "web-prod")).TerraformVersion = "1.10.2"
+	h := newHarness(t, &config.Config{Organization: "acme"}, c)
+	h.waitFor("web-prod")
+
+	// terraform
+	h.pressRune('$')
+	h.waitFor("TERRAFORM ▲")
+	text := h.text()
+	assert.Less(t, strings.Index(text, "app-dev"), strings.Index(text, "web-prod"))
+
+	h.pressRune('$')
+	h.waitFor("TERRAFORM ▼")
+	text = h.text()
+	assert.Greater(t, strings.Index(text, "app-dev"), strings.Index(text, "web-prod"))
+
+	// count
+	h.pressRune('%')
+	h.waitFor("COUNT ▲")
+	assert.NotContains(t, h.text(), "TERRAFORM ▼")
+	text = h.text()
+	assert.Greater(t, strings.Index(text, "app-prod"), strings.Index(text, "app-dev"))
+
+	// the selection follows the rows
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-dev", h.config.Workspace)
+}
+
+func TestWorkspacesPageFilter(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('f')
+	h.typeText("PROD")
+	h.waitForGone("app-dev")
+	h.waitFor("total workspaces: 2, shown: 1")
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("filter: PROD")
+
+	h.pressKey(tcell.KeyEnter)
+	h.waitFor("workspace details")
+	assert.Equal(t, "app-prod", h.config.Workspace)
+
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("filter: PROD")
+	h.pressRune('f')
+	h.pressKey(tcell.KeyEsc)
+	h.waitFor("app-dev")
+	assert.NotContains(t, h.text(), "shown:")
+}
+
+func TestWorkspacesPageFilterNoRows(t *testing.T) {
+	h := newHarness(t, &config.Config{Organization: "acme"}, newFakeClient())
+	h.waitFor("app-dev")
+
+	h.pressRune('f')
+	h.typeText("nothing")
+	h.waitFor("total workspaces: 2, shown: 0")
+	h.pressKey(tcell.KeyEnter)
+	h.pressKey(tcell.KeyEnter)
+
+	h.waitFor("filter: nothing")
+	h.onUI(func() {
+		assert.Equal(t, WorkspacesPageName, h.app.currentPage.Name())
+	})
+}
+
+func TestNaturalCompare(t *testing.T) {
+	assert.Negative(t, naturalCompare("1.9.0", "1.10.0"))
+	assert.Negative(t, naturalCompare("2", "12"))
+	assert.Negative(t, naturalCompare("app-dev", "App-prod"))
+	assert.Negative(t, naturalCompare("app", "app-dev"))
+	assert.Positive(t, naturalCompare("v010", "v9"))
+	assert.Zero(t, naturalCompare("007", "7"))
+	assert.Negative(t, naturalCompare("", "a"))
+}
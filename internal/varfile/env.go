@@ -0,0 +1,135 @@
+package varfile
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+var envKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func writeEnv(w io.Writer, variables []*tfe.Variable) error {
+	for _, v := range variables {
+		value := quoteEnv(v.Value)
+		if v.Sensitive {
+			value = Placeholder
+		}
+
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.Key, value); err != nil {
+			return fmt.Errorf("error writing the variable %s: %w", v.Key, err)
+		}
+	}
+	return nil
+}
+
+// quoteEnv double quotes the value if it has spaces, quotes or other
+// characters the shells and the dotenv libraries read differently.
+func quoteEnv(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r\"'#\\$`") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"$", `\$`,
+		"`", "\\`",
+	)
+	return fmt.Sprintf(`"%s"`, replacer.Replace(value))
+}
+
+// parseEnv reads the `KEY=value` lines of a .env file, with an optional
+// export, double quoted values with escapes, single quoted values kept as is
+// and comments.
+func parseEnv(text string) ([]*Variable, error) {
+	variables := []*Variable{}
+	for i, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		v, err := parseEnvLine(strings.TrimPrefix(line, "export "))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		variables = append(variables, v)
+	}
+	return variables, nil
+}
+
+func parseEnvLine(line string) (*Variable, error) {
+	equal := strings.IndexByte(line, '=')
+	if equal == -1 {
+		return nil, fmt.Errorf("expected KEY=value")
+	}
+
+	key := strings.TrimSpace(line[:equal])
+	if !envKeyRegexp.MatchString(key) {
+		return nil, fmt.Errorf("invalid variable name %q", key)
+	}
+
+	value, err := envValue(strings.TrimSpace(line[equal+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	if value == Placeholder {
+		return &Variable{Key: key, Sensitive: true}, nil
+	}
+	return &Variable{Key: key, Value: value}, nil
+}
+
+func envValue(text string) (string, error) {
+	switch {
+	case strings.HasPrefix(text, `'`):
+		end := strings.IndexByte(text[1:], '\'')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return text[1 : end+1], checkEnvRest(text[end+2:])
+	case strings.HasPrefix(text, `"`):
+		var b strings.Builder
+		for i := 1; i < len(text); i++ {
+			switch {
+			case text[i] == '"':
+				return b.String(), checkEnvRest(text[i+1:])
+			case text[i] == '\\' && i+1 < len(text):
+				i++
+				switch text[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(text[i])
+				}
+			default:
+				b.WriteByte(text[i])
+			}
+		}
+		return "", fmt.Errorf("unterminated string")
+	}
+
+	if comment := strings.Index(text, " #"); comment != -1 {
+		text = text[:comment]
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// checkEnvRest fails if there is text other than a comment after a quoted
+// value.
+func checkEnvRest(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected text after the value")
+	}
+	return nil
+}
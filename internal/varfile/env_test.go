@@ -0,0 +1,77 @@
+package varfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEnv(t *testing.T) {
+	variables := []*tfe.Variable{
+		{Key: "TF_LOG", Value: "", Category: tfe.CategoryEnv},
+		{Key: "AWS_REGION", Value: "eu-west-1", Category: tfe.CategoryEnv},
+		{Key: "AWS_SECRET_ACCESS_KEY", Value: "", Category: tfe.CategoryEnv, Sensitive: true},
+		{Key: "GREETING", Value: "say \"hi\" to $USER\n", Category: tfe.CategoryEnv},
+		{Key: "region", Value: "eu-west-1", Category: tfe.CategoryTerraform},
+	}
+
+	var b bytes.Buffer
+	n, err := Write(&b, FormatEnv, variables)
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, `AWS_REGION=eu-west-1
+AWS_SECRET_ACCESS_KEY=<sensitive>
+GREETING="say \"hi\" to \$USER\n"
+TF_LOG=""
+`, b.String())
+
+	parsed, err := Parse(FormatEnv, b.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []*Variable{
+		{Key: "AWS_REGION", Value: "eu-west-1"},
+		{Key: "AWS_SECRET_ACCESS_KEY", Sensitive: true},
+		{Key: "GREETING", Value: "say \"hi\" to $USER\n"},
+		{Key: "TF_LOG", Value: ""},
+	}, parsed)
+}
+
+func TestParseEnv(t *testing.T) {
+	text := `# exported from app-prod
+export AWS_REGION=eu-west-1 # the default region
+
+TF_CLI_ARGS = '-no-color $HOME'
+GREETING="a # not a comment" # a comment
+`
+
+	variables, err := Parse(FormatEnv, []byte(text))
+	require.NoError(t, err)
+	assert.Equal(t, []*Variable{
+		{Key: "AWS_REGION", Value: "eu-west-1"},
+		{Key: "TF_CLI_ARGS", Value: "-no-color $HOME"},
+		{Key: "GREETING", Value: "a # not a comment"},
+	}, variables)
+}
+
+func TestParseEnvErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "missing equal", text: "AWS_REGION", expected: "line 1: expected KEY=value"},
+		{name: "invalid name", text: "\nAWS-REGION=eu", expected: `line 2: invalid variable name "AWS-REGION"`},
+		{name: "unterminated string", text: `A="eu`, expected: "line 1: invalid value of A: unterminated string"},
+		{name: "text after the value", text: `A="eu" west`, expected: "line 1: invalid value of A: unexpected text after the value"},
+		{name: "duplicate", text: "A=1\nA=2", expected: "duplicate variable A"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(FormatEnv, []byte(tc.text))
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package varfile
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-tfe"
+
+	"github.com/renato0307/terrui/internal/client"
+)
+
+// Action is what importing a file does to a workspace variable.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionSkip   Action = "skip"
+)
+
+// Change is a change to a workspace variable to import a file.
+type Change struct {
+	Action Action
+	Key    string
+
+	// Variable is the workspace variable changed, nil for the creates.
+	Variable *tfe.Variable
+
+	// Options are the options to create or update the variable.
+	Options client.VariableOptions
+
+	// Reason is why the variable is skipped.
+	Reason string
+}
+
+// String returns the change as a line of the import preview.
+func (c *Change) String() string {
+	switch c.Action {
+	case ActionCreate:
+		return fmt.Sprintf("+ create %s", c.Key)
+	case ActionUpdate:
+		return fmt.Sprintf("~ update %s", c.Key)
+	case ActionDelete:
+		return fmt.Sprintf("- delete %s", c.Key)
+	}
+	return fmt.Sprintf("! skip %s: %s", c.Key, c.Reason)
+}
+
+// Plan returns the changes to import the variables of a file in the category
+// into the workspace variables, sorted by key. The variables with the same
+// value are left as they are, the sensitive variables are updated as their
+// value can't be compared and the placeholders of the sensitive values are
+// skipped. The workspace variables of the category missing in the file are
+// deleted if deleteMissing is set.
+func Plan(existing []*tfe.Variable, variables []*Variable, category tfe.CategoryType, deleteMissing bool) []*Change {
+	byKey := map[string]*tfe.Variable{}
+	for _, v := range existing {
+		if v.Category == category {
+			byKey[v.Key] = v
+		}
+	}
+
+	changes := []*Change{}
+	imported := map[string]bool{}
+	for _, v := range variables {
+		imported[v.Key] = true
+		current := byKey[v.Key]
+
+		if v.Sensitive {
+			if current == nil {
+				changes = append(changes, &Change{Action: ActionSkip, Key: v.Key, Reason: "the sensitive value is not in the file"})
+			}
+			continue
+		}
+
+		value := v.Value
+		if current == nil {
+			changes = append(changes, &Change{
+				Action: ActionCreate,
+				Key:    v.Key,
+				Options: client.VariableOptions{
+					Key:      v.Key,
+					Value:    &value,
+					Category: category,
+					HCL:      v.HCL,
+				},
+			})
+			continue
+		}
+
+		if !current.Sensitive && current.Value == v.Value && current.HCL == v.HCL {
+			continue
+		}
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Key:      v.Key,
+			Variable: current,
+			Options: client.VariableOptions{
+				Key:         v.Key,
+				Value:       &value,
+				Description: current.Description,
+				Category:    category,
+				HCL:         v.HCL,
+				Sensitive:   current.Sensitive,
+			},
+		})
+	}
+
+	if deleteMissing {
+		for _, v := range existing {
+			if v.Category == category && !imported[v.Key] {
+				changes = append(changes, &Change{Action: ActionDelete, Key: v.Key, Variable: v})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
@@ -0,0 +1,65 @@
+package varfile
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	existing := []*tfe.Variable{
+		{ID: "var-1", Key: "region", Value: "eu-west-1", Category: tfe.CategoryTerraform},
+		{ID: "var-2", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+		{ID: "var-3", Key: "api_key", Category: tfe.CategoryTerraform, Sensitive: true, Description: "the API key"},
+		{ID: "var-4", Key: "zones", Value: `["a"]`, Category: tfe.CategoryTerraform, HCL: true},
+		{ID: "var-5", Key: "legacy", Value: "true", Category: tfe.CategoryTerraform},
+		{ID: "var-6", Key: "AWS_REGION", Value: "eu-west-1", Category: tfe.CategoryEnv},
+	}
+	variables := []*Variable{
+		{Key: "region", Value: "eu-west-1"},
+		{Key: "db_password", Sensitive: true},
+		{Key: "api_key", Value: "s3cr3t"},
+		{Key: "zones", Value: `["a", "b"]`, HCL: true},
+		{Key: "instance_type", Value: "t3.large"},
+		{Key: "token", Sensitive: true},
+	}
+
+	lines := func(changes []*Change) []string {
+		result := []string{}
+		for _, c := range changes {
+			result = append(result, c.String())
+		}
+		return result
+	}
+
+	changes := Plan(existing, variables, tfe.CategoryTerraform, false)
+	assert.Equal(t, []string{
+		"~ update api_key",
+		"+ create instance_type",
+		"! skip token: the sensitive value is not in the file",
+		"~ update zones",
+	}, lines(changes))
+
+	require.Equal(t, "var-3", changes[0].Variable.ID)
+	assert.Equal(t, "s3cr3t", *changes[0].Options.Value)
+	assert.True(t, changes[0].Options.Sensitive)
+	assert.Equal(t, "the API key", changes[0].Options.Description)
+
+	assert.Nil(t, changes[1].Variable)
+	assert.Equal(t, tfe.CategoryTerraform, changes[1].Options.Category)
+	assert.Equal(t, "t3.large", *changes[1].Options.Value)
+
+	assert.Equal(t, []string{
+		"~ update api_key",
+		"+ create instance_type",
+		"- delete legacy",
+		"! skip token: the sensitive value is not in the file",
+		"~ update zones",
+	}, lines(Plan(existing, variables, tfe.CategoryTerraform, true)))
+
+	assert.Equal(t, []string{
+		"- delete AWS_REGION",
+	}, lines(Plan(existing, nil, tfe.CategoryEnv, true)))
+}
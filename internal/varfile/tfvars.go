@@ -0,0 +1,233 @@
+package varfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var primitiveRegexp = regexp.MustCompile(`^(true|false|-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?)$`)
+
+func writeTFVars(w io.Writer, variables []*tfe.Variable) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for _, v := range variables {
+		switch {
+		case v.Sensitive:
+			body.SetAttributeValue(v.Key, cty.StringVal(Placeholder))
+		case !v.HCL:
+			body.SetAttributeValue(v.Key, cty.StringVal(v.Value))
+		default:
+			tokens, err := hclTokens(v.Value)
+			if err != nil {
+				return fmt.Errorf("the HCL value of %s is not valid: %w", v.Key, err)
+			}
+			body.SetAttributeRaw(v.Key, tokens)
+		}
+	}
+
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("error writing the variables: %w", err)
+	}
+	return nil
+}
+
+// hclTokens returns the tokens of the HCL value as it is, comments included.
+// An empty value is unset, so it is written as null.
+func hclTokens(value string) (hclwrite.Tokens, error) {
+	if strings.TrimSpace(value) == "" {
+		value = "null"
+	}
+
+	src := []byte(fmt.Sprintf("value = %s\n", value))
+	if _, diags := hclsyntax.ParseConfig(src, "", hcl.InitialPos); diags.HasErrors() {
+		return nil, diagnosticsMessageError(diags)
+	}
+	f, diags := hclwrite.ParseConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diagnosticsMessageError(diags)
+	}
+	return f.Body().GetAttribute("value").Expr().BuildTokens(nil), nil
+}
+
+// parseTFVars reads the `key = value` assignments of a .tfvars file. The
+// strings, numbers and bools are read as values, any other expression, like a
+// list, a map or null, is kept as an HCL value, as it is written in the file.
+func parseTFVars(data []byte) ([]*Variable, error) {
+	f, diags := hclsyntax.ParseConfig(data, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diagnosticsError(diags)
+	}
+
+	body := f.Body.(*hclsyntax.Body)
+	if len(body.Blocks) > 0 {
+		block := body.Blocks[0]
+		return nil, fmt.Errorf("line %d: unexpected block %s, only variable assignments are allowed", block.TypeRange.Start.Line, block.Type)
+	}
+
+	attributes := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, a := range body.Attributes {
+		attributes = append(attributes, a)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].SrcRange.Start.Byte < attributes[j].SrcRange.Start.Byte
+	})
+
+	variables := []*Variable{}
+	for _, a := range attributes {
+		v, err := tfvarsVariable(data, a)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid value of %s: %w", a.SrcRange.Start.Line, a.Name, err)
+		}
+		variables = append(variables, v)
+	}
+	return variables, nil
+}
+
+// tfvarsVariable returns the variable of the assignment. Like terraform, the
+// values can't refer to variables or call functions.
+func tfvarsVariable(data []byte, a *hclsyntax.Attribute) (*Variable, error) {
+	if traversals := a.Expr.Variables(); len(traversals) > 0 {
+		return nil, fmt.Errorf("variables are not allowed, found %s", traversals[0].RootName())
+	}
+
+	value, diags := a.Expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diagnosticsMessageError(diags)
+	}
+
+	if !value.IsNull() && value.IsKnown() {
+		switch value.Type() {
+		case cty.String:
+			if value.AsString() == Placeholder {
+				return &Variable{Key: a.Name, Sensitive: true}, nil
+			}
+			return &Variable{Key: a.Name, Value: value.AsString()}, nil
+		case cty.Number:
+			return &Variable{Key: a.Name, Value: value.AsBigFloat().Text('f', -1)}, nil
+		case cty.Bool:
+			return &Variable{Key: a.Name, Value: strconv.FormatBool(value.True())}, nil
+		}
+	}
+
+	expression := strings.TrimSpace(string(a.Expr.Range().SliceBytes(data)))
+	return &Variable{Key: a.Name, Value: expression, HCL: true}, nil
+}
+
+// diagnosticsError returns the first error of the diagnostics, with its line.
+func diagnosticsError(diags hcl.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError && d.Subject != nil {
+			return fmt.Errorf("line %d: %s", d.Subject.Start.Line, diagnosticMessage(d))
+		}
+	}
+	return diagnosticsMessageError(diags)
+}
+
+// diagnosticsMessageError returns the first error of the diagnostics,
+// without its line.
+func diagnosticsMessageError(diags hcl.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError {
+			return errors.New(diagnosticMessage(d))
+		}
+	}
+	return diags
+}
+
+func diagnosticMessage(d *hcl.Diagnostic) string {
+	if d.Detail == "" {
+		return d.Summary
+	}
+	return fmt.Sprintf("%s: %s", d.Summary, strings.TrimSuffix(d.Detail, "."))
+}
+
+// writeJSON writes the variables as a .tfvars.json object, the HCL values
+// must be valid JSON, like lists of strings.
+func writeJSON(w io.Writer, variables []*tfe.Variable) error {
+	object := map[string]json.RawMessage{}
+	for _, v := range variables {
+		var value interface{} = v.Value
+		switch {
+		case v.Sensitive:
+			value = Placeholder
+		case v.HCL:
+			if !json.Valid([]byte(v.Value)) {
+				return fmt.Errorf("the HCL value of %s is not valid JSON, export it to a .tfvars file", v.Key)
+			}
+			value = json.RawMessage(v.Value)
+		}
+
+		var raw bytes.Buffer
+		if err := newJSONEncoder(&raw).Encode(value); err != nil {
+			return fmt.Errorf("error encoding the variable %s: %w", v.Key, err)
+		}
+		object[v.Key] = raw.Bytes()
+	}
+
+	encoder := newJSONEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(object); err != nil {
+		return fmt.Errorf("error writing the variables: %w", err)
+	}
+	return nil
+}
+
+// newJSONEncoder returns an encoder keeping the <, > and & characters, like
+// the ones of the placeholder, as they are.
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder
+}
+
+// parseJSON reads a .tfvars.json object, the strings, numbers and bools are
+// read as values and the lists, objects and nulls as HCL values, JSON being
+// valid HCL.
+func parseJSON(data []byte) ([]*Variable, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("error decoding the JSON variables: %w", err)
+	}
+
+	keys := []string{}
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	variables := []*Variable{}
+	for _, k := range keys {
+		raw := object[k]
+
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			if text == Placeholder {
+				variables = append(variables, &Variable{Key: k, Sensitive: true})
+			} else {
+				variables = append(variables, &Variable{Key: k, Value: text})
+			}
+			continue
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return nil, fmt.Errorf("error decoding the variable %s: %w", k, err)
+		}
+		value := compact.String()
+		variables = append(variables, &Variable{Key: k, Value: value, HCL: !primitiveRegexp.MatchString(value)})
+	}
+	return variables, nil
+}
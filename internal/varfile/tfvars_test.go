@@ -0,0 +1,205 @@
+package varfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportedVariables() []*tfe.Variable {
+	return []*tfe.Variable{
+		{Key: "region", Value: "eu-west-1", Category: tfe.CategoryTerraform},
+		{Key: "db_password", Value: "", Category: tfe.CategoryTerraform, Sensitive: true},
+		{Key: "tags", Value: "{\n  team = \"platform\"\n}", Category: tfe.CategoryTerraform, HCL: true},
+		{Key: "zones", Value: `["a", "b"]`, Category: tfe.CategoryTerraform, HCL: true},
+		{Key: "greeting", Value: "say \"hi\"\n${name}", Category: tfe.CategoryTerraform},
+		{Key: "AWS_REGION", Value: "eu-west-1", Category: tfe.CategoryEnv},
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected Format
+	}{
+		{path: "prod.tfvars", expected: FormatTFVars},
+		{path: "prod.tfvars.json", expected: FormatJSON},
+		{path: "/tmp/.env", expected: FormatEnv},
+		{path: "prod.ENV", expected: FormatEnv},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			format, err := FormatOf(tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+
+	_, err := FormatOf("prod.yaml")
+	assert.EqualError(t, err, "unknown format of prod.yaml, use a .tfvars, .tfvars.json or .env file")
+	_, err = FormatOf("package.json")
+	assert.EqualError(t, err, "unknown format of package.json, use a .tfvars, .tfvars.json or .env file")
+}
+
+func TestWriteTFVars(t *testing.T) {
+	var b bytes.Buffer
+	n, err := Write(&b, FormatTFVars, exportedVariables())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, `db_password = "<sensitive>"
+greeting    = "say \"hi\"\n$${name}"
+region      = "eu-west-1"
+tags = {
+  team = "platform"
+}
+zones = ["a", "b"]
+`, b.String())
+}
+
+func TestWriteTFVarsHCL(t *testing.T) {
+	var b bytes.Buffer
+	_, err := Write(&b, FormatTFVars, []*tfe.Variable{
+		{Key: "empty", Value: "", Category: tfe.CategoryTerraform, HCL: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "empty = null\n", b.String())
+
+	_, err = Write(&b, FormatTFVars, []*tfe.Variable{
+		{Key: "zones", Value: `["a",`, Category: tfe.CategoryTerraform, HCL: true},
+	})
+	assert.EqualError(t, err, "the HCL value of zones is not valid: Missing expression: Expected the start of an expression, but found the end of the file")
+}
+
+func TestParseTFVars(t *testing.T) {
+	text := `# exported from app-prod
+region = "eu-west-1" # the default region
+count  = 3
+enabled = true
+db_password = "<sensitive>"
+greeting = "say \"hi\"\n$${name} é"
+tags = {
+  team = "platform" # owner
+  // the cost center
+  cost = "42}"
+}
+/* multi-line
+   comment */
+zones = ["a", "b"]
+motd = <<-EOT
+    hello
+      world
+    EOT
+blank = <<-EOT
+
+
+EOT
+nothing = null
+ratio = -1.50
+`
+
+	variables, err := Parse(FormatTFVars, []byte(text))
+	require.NoError(t, err)
+	assert.Equal(t, []*Variable{
+		{Key: "region", Value: "eu-west-1"},
+		{Key: "count", Value: "3"},
+		{Key: "enabled", Value: "true"},
+		{Key: "db_password", Sensitive: true},
+		{Key: "greeting", Value: "say \"hi\"\n${name} é"},
+		{Key: "tags", Value: "{\n  team = \"platform\" # owner\n  // the cost center\n  cost = \"42}\"\n}", HCL: true},
+		{Key: "zones", Value: `["a", "b"]`, HCL: true},
+		{Key: "motd", Value: "hello\n  world\n"},
+		{Key: "blank", Value: "\n\n"},
+		{Key: "nothing", Value: "null", HCL: true},
+		{Key: "ratio", Value: "-1.5"},
+	}, variables)
+}
+
+func TestParseTFVarsRoundTrip(t *testing.T) {
+	var b bytes.Buffer
+	_, err := Write(&b, FormatTFVars, exportedVariables())
+	require.NoError(t, err)
+
+	variables, err := Parse(FormatTFVars, b.Bytes())
+	require.NoError(t, err)
+	require.Len(t, variables, 5)
+	assert.Equal(t, &Variable{Key: "greeting", Value: "say \"hi\"\n${name}"}, variables[1])
+	assert.Equal(t, &Variable{Key: "tags", Value: "{\n  team = \"platform\"\n}", HCL: true}, variables[3])
+}
+
+func TestParseTFVarsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "missing equal", text: "region \"eu-west-1\"", expected: "line 1: Invalid block definition: Either a quoted string block label or an opening brace (\"{\") is expected here"},
+		{name: "missing value", text: "\nregion =\n", expected: "line 2: Invalid expression: Expected the start of an expression, but found an invalid expression token"},
+		{name: "unterminated string", text: `region = "eu-west-1`, expected: "line 1: Unterminated template string: No closing marker was found for the string"},
+		{name: "unclosed bracket", text: "zones = [\"a\",\n", expected: "line 2: Missing expression: Expected the start of an expression, but found the end of the file"},
+		{name: "text after the value", text: `region = "eu" "west"`, expected: "line 1: Missing newline after argument: An argument definition must end with a newline"},
+		{name: "duplicate", text: "a = 1\na = 2", expected: "line 2: Attribute redefined: The argument \"a\" was already set at :1,1-2. Each argument may be set only once"},
+		{name: "unterminated comment", text: "/* a = 1", expected: "line 1: Argument or block definition required: An argument or block definition is required here"},
+		{name: "heredoc marker with spaces", text: "motd = <<EOT  \nhello\nEOT\n", expected: "line 1: Invalid expression: Expected the start of an expression, but found an invalid expression token"},
+		{name: "variable", text: "region = default_region", expected: "line 1: invalid value of region: variables are not allowed, found default_region"},
+		{name: "function", text: `region = lower("EU")`, expected: "line 1: invalid value of region: Function calls not allowed: Functions may not be called here"},
+		{name: "block", text: "tags {\n}\n", expected: "line 1: unexpected block tags, only variable assignments are allowed"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(FormatTFVars, []byte(tc.text))
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	_, err := Write(&b, FormatJSON, exportedVariables())
+	assert.EqualError(t, err, "the HCL value of tags is not valid JSON, export it to a .tfvars file")
+
+	variables := exportedVariables()
+	variables[2].Value = `{"team": "platform"}`
+	b.Reset()
+	n, err := Write(&b, FormatJSON, variables)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, `{
+  "db_password": "<sensitive>",
+  "greeting": "say \"hi\"\n${name}",
+  "region": "eu-west-1",
+  "tags": {
+    "team": "platform"
+  },
+  "zones": [
+    "a",
+    "b"
+  ]
+}
+`, b.String())
+}
+
+func TestParseJSON(t *testing.T) {
+	text := `{
+  "region": "eu-west-1",
+  "count": 3,
+  "db_password": "<sensitive>",
+  "zones": ["a", "b"]
+}`
+
+	variables, err := Parse(FormatJSON, []byte(text))
+	require.NoError(t, err)
+	assert.Equal(t, []*Variable{
+		{Key: "count", Value: "3"},
+		{Key: "db_password", Sensitive: true},
+		{Key: "region", Value: "eu-west-1"},
+		{Key: "zones", Value: `["a","b"]`, HCL: true},
+	}, variables)
+
+	_, err = Parse(FormatJSON, []byte(`["a"]`))
+	assert.Error(t, err)
+}
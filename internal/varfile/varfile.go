@@ -0,0 +1,117 @@
+// Package varfile reads and writes the workspace variables as .tfvars,
+// .tfvars.json and .env files, and plans the changes to import a file into a
+// workspace.
+package varfile
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// Placeholder is written in place of the values of the sensitive variables,
+// which are write only. The variables with it are not imported.
+const Placeholder = "<sensitive>"
+
+// Format is a variables file format.
+type Format string
+
+const (
+	FormatTFVars Format = "tfvars"
+	FormatJSON   Format = "tfvars.json"
+	FormatEnv    Format = "env"
+)
+
+// FormatOf returns the format of the file from its extension.
+func FormatOf(path string) (Format, error) {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(name, ".tfvars"):
+		return FormatTFVars, nil
+	case strings.HasSuffix(name, ".tfvars.json"):
+		return FormatJSON, nil
+	case strings.HasSuffix(name, ".env"):
+		return FormatEnv, nil
+	}
+	return "", fmt.Errorf("unknown format of %s, use a .tfvars, .tfvars.json or .env file", path)
+}
+
+// Category returns the category of the variables of the format, the .env
+// files have the environment variables and the others the terraform ones.
+func (f Format) Category() tfe.CategoryType {
+	if f == FormatEnv {
+		return tfe.CategoryEnv
+	}
+	return tfe.CategoryTerraform
+}
+
+// Variable is a variable of a file. Sensitive is set if the file has the
+// placeholder in place of the value.
+type Variable struct {
+	Key       string
+	Value     string
+	HCL       bool
+	Sensitive bool
+}
+
+// Write writes the variables of the format category, sorted by key, and
+// returns how many were written.
+func Write(w io.Writer, format Format, variables []*tfe.Variable) (int, error) {
+	selected := []*tfe.Variable{}
+	for _, v := range variables {
+		if v.Category == format.Category() {
+			selected = append(selected, v)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Key < selected[j].Key
+	})
+
+	var err error
+	switch format {
+	case FormatTFVars:
+		err = writeTFVars(w, selected)
+	case FormatJSON:
+		err = writeJSON(w, selected)
+	case FormatEnv:
+		err = writeEnv(w, selected)
+	default:
+		err = fmt.Errorf("unknown format %s", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(selected), nil
+}
+
+// Parse reads the variables of a file in the format.
+func Parse(format Format, data []byte) ([]*Variable, error) {
+	var variables []*Variable
+	var err error
+	switch format {
+	case FormatTFVars:
+		variables, err = parseTFVars(data)
+	case FormatJSON:
+		variables, err = parseJSON(data)
+	case FormatEnv:
+		variables, err = parseEnv(string(data))
+	default:
+		err = fmt.Errorf("unknown format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, v := range variables {
+		if seen[v.Key] {
+			return nil, fmt.Errorf("duplicate variable %s", v.Key)
+		}
+		seen[v.Key] = true
+	}
+	return variables, nil
+}
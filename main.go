@@ -1,11 +1,19 @@
 package main
 
 import (
+	"flag"
+
 	"github.com/renato0307/terrui/internal/ui"
 )
 
 func main() {
-	app := ui.NewApp()
+	profile := flag.String("profile", "", "name of the connection profile to use")
+	flag.Parse()
+
+	app, err := ui.NewApp(*profile)
+	if err != nil {
+		panic(err)
+	}
 
 	if err := app.Run(); err != nil {
 		panic(err)